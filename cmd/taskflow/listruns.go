@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+var listRunsCmd = &cobra.Command{
+	Use:   "list-runs",
+	Short: "List checkpointed workflow runs",
+	Long:  `List every run recorded in the state backend, across all workflows.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backend, err := state.Open(stateBackend, stateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer backend.Close()
+
+		runs, err := backend.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(runs) == 0 {
+			fmt.Println("No runs recorded.")
+			return
+		}
+
+		fmt.Printf("%-24s %-24s %-12s %s\n", "RUN ID", "WORKFLOW", "TASKS", "UPDATED")
+		for _, run := range runs {
+			fmt.Printf("%-24s %-24s %-12d %s\n", run.RunID, run.WorkflowName, len(run.Results), run.UpdatedAt.Format("2006-01-02 15:04:05"))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listRunsCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/BaseMax/go-taskflow/pkg/logger"
+	"github.com/BaseMax/go-taskflow/pkg/parser"
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/BaseMax/go-taskflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry [run-id] [workflow-file]",
+	Short: "Resume a checkpointed run from its workflow file",
+	Long: `Re-run a workflow, skipping any task that completed successfully in a
+previous checkpoint for run-id. The original workflow file must be given
+again since state backends only persist task results, not the YAML itself.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		targetID, workflowFile := args[0], args[1]
+		log := logger.New(logger.ParseLevel(logLevel), logger.Format(logFormat), os.Stderr)
+
+		wf, err := parser.ParseWorkflowFile(workflowFile)
+		if err != nil {
+			log.Error("failed to parse workflow", logger.Fields{"file": workflowFile, "error": err.Error()})
+			os.Exit(1)
+		}
+
+		backend, err := state.Open(stateBackend, stateFile)
+		if err != nil {
+			log.Error("failed to open state backend", logger.Fields{"error": err.Error()})
+			os.Exit(1)
+		}
+		defer backend.Close()
+
+		engine := workflow.NewEngine(wf)
+		engine.SetLogger(log)
+		registerPlugins(engine, wf)
+		engine.EnableState(backend, targetID, true)
+
+		log.Info("resuming run", logger.Fields{"run_id": targetID, "workflow": wf.Name})
+		results, runErr := engine.Run(context.Background())
+
+		successCount := 0
+		for _, result := range results {
+			if result.Success {
+				successCount++
+			}
+		}
+		fmt.Printf("Resumed run %s: %d/%d tasks succeeded\n", targetID, successCount, len(results))
+
+		if runErr != nil {
+			log.Error("run completed with errors", logger.Fields{"error": runErr.Error()})
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+}
@@ -3,12 +3,23 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/BaseMax/go-taskflow/pkg/plugin"
+	"github.com/BaseMax/go-taskflow/pkg/types"
+	"github.com/BaseMax/go-taskflow/pkg/workflow"
 	"github.com/spf13/cobra"
 )
 
 var version = "1.0.0"
 
+// stateBackend and stateFile are shared by every subcommand that reads or
+// writes persisted run state (run --resume, list-runs, show, retry).
+var (
+	stateBackend string
+	stateFile    string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "taskflow",
 	Short: "TaskFlow - A declarative task automation tool",
@@ -30,4 +41,25 @@ func init() {
 	rootCmd.SetVersionTemplate(`TaskFlow {{.Version}}
 A declarative task automation tool for developers and ops teams.
 `)
+	rootCmd.PersistentFlags().StringVar(&stateBackend, "state-backend", "bolt", "run state backend (only \"bolt\" today)")
+	rootCmd.PersistentFlags().StringVar(&stateFile, "state-file", "taskflow.db", "path to the run state file")
+}
+
+// unixSocketPrefix marks a `plugins:` entry as a Unix socket address to
+// dial (e.g. `docker: unix:///var/run/taskflow-docker.sock`) rather than
+// a command to spawn over stdio.
+const unixSocketPrefix = "unix://"
+
+// registerPlugins wires any `plugins:` declared in wf into engine so task
+// types matching a plugin name are routed to that out-of-process handler.
+func registerPlugins(engine *workflow.Engine, wf *types.Workflow) {
+	for name, target := range wf.Plugins {
+		cfg := plugin.Config{Name: name}
+		if socket, ok := strings.CutPrefix(target, unixSocketPrefix); ok {
+			cfg.Socket = socket
+		} else {
+			cfg.Command = target
+		}
+		engine.RegisterPlugin(name, plugin.New(cfg))
+	}
 }
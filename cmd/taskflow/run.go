@@ -4,13 +4,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/BaseMax/go-taskflow/pkg/logger"
 	"github.com/BaseMax/go-taskflow/pkg/parser"
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/BaseMax/go-taskflow/pkg/types"
 	"github.com/BaseMax/go-taskflow/pkg/workflow"
 	"github.com/spf13/cobra"
 )
 
+var (
+	logFormat       string
+	logLevel        string
+	resume          bool
+	runID           string
+	maxProcs        int
+	shutdownTimeout time.Duration
+)
+
 var runCmd = &cobra.Command{
 	Use:   "run [workflow-file]",
 	Short: "Run a workflow from a YAML file",
@@ -18,31 +33,62 @@ var runCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		workflowFile := args[0]
+		log := logger.New(logger.ParseLevel(logLevel), logger.Format(logFormat), os.Stderr)
 
-		// Parse workflow
-		fmt.Printf("📋 Loading workflow from: %s\n", workflowFile)
+		log.Info("loading workflow", logger.Fields{"file": workflowFile})
 		wf, err := parser.ParseWorkflowFile(workflowFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Error parsing workflow: %v\n", err)
+			log.Error("failed to parse workflow", logger.Fields{"file": workflowFile, "error": err.Error()})
 			os.Exit(1)
 		}
 
-		fmt.Printf("📝 Workflow: %s\n", wf.Name)
-		if wf.Description != "" {
-			fmt.Printf("   %s\n", wf.Description)
-		}
-		fmt.Printf("   Tasks: %d\n\n", len(wf.Tasks))
+		log.Info("workflow loaded", logger.Fields{"name": wf.Name, "tasks": len(wf.Tasks)})
 
 		// Create engine and run workflow
 		engine := workflow.NewEngine(wf)
-		ctx := context.Background()
+		engine.SetLogger(log)
+		engine.SetMaxParallel(maxProcs)
+		registerPlugins(engine, wf)
+
+		backend, err := state.Open(stateBackend, stateFile)
+		if err != nil {
+			log.Error("failed to open state backend", logger.Fields{"error": err.Error()})
+			os.Exit(1)
+		}
+		defer backend.Close()
+
+		if runID == "" {
+			if resume {
+				latest, err := latestRunID(backend, wf.Name)
+				if err != nil {
+					log.Error("cannot resume: no run-id given and none could be found", logger.Fields{"workflow": wf.Name, "error": err.Error()})
+					os.Exit(1)
+				}
+				runID = latest
+			} else {
+				runID = newRunID()
+			}
+		}
+		engine.EnableState(backend, runID, resume)
+		log.Info("run state enabled", logger.Fields{"run_id": runID, "resume": resume})
+
+		events := engine.Subscribe(make(chan workflow.Event, 64))
+		progressDone := make(chan struct{})
+		if logFormat == string(logger.FormatText) {
+			go renderProgress(events, len(wf.Tasks), progressDone)
+		} else {
+			go drainEvents(events, progressDone)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
 		startTime := time.Now()
-		fmt.Println("🚀 Starting workflow execution...")
-		fmt.Println()
+		log.Info("starting workflow execution", nil)
+
+		results, runErr := runWithShutdownTimeout(ctx, engine, log)
+		<-progressDone
 
-		results, err := engine.Run(ctx)
-		
 		elapsed := time.Since(startTime)
 		fmt.Println()
 		fmt.Println("═══════════════════════════════════════════")
@@ -74,8 +120,8 @@ var runCmd = &cobra.Command{
 		fmt.Printf("Total time: %.2fs\n", elapsed.Seconds())
 		fmt.Println("═══════════════════════════════════════════")
 
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "\n⚠️  Workflow completed with errors: %v\n", err)
+		if runErr != nil {
+			log.Error("workflow completed with errors", logger.Fields{"error": runErr.Error()})
 			os.Exit(1)
 		}
 
@@ -83,10 +129,120 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Println("\n✨ Workflow completed successfully!")
+		log.Info("workflow completed successfully", nil)
 	},
 }
 
+// renderProgress draws an ASCII `executed / total` progress bar to stderr
+// as TaskFinished events arrive, until the engine closes events.
+func renderProgress(events <-chan workflow.Event, total int, done chan<- struct{}) {
+	defer close(done)
+
+	executedCount := 0
+	for evt := range events {
+		if evt.Type != workflow.EventTaskFinished {
+			continue
+		}
+		executedCount++
+		printProgressBar(executedCount, total)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// printProgressBar renders a fixed-width ASCII progress bar in place.
+func printProgressBar(done, total int) {
+	const width = 30
+	if total == 0 {
+		return
+	}
+
+	filled := width * done / total
+	if filled > width {
+		filled = width
+	}
+
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+	fmt.Fprintf(os.Stderr, "\r[%s] %d/%d", bar, done, total)
+}
+
+// drainEvents discards events without rendering a text progress bar,
+// used when --log-format=json so stderr carries only JSON log lines.
+func drainEvents(events <-chan workflow.Event, done chan<- struct{}) {
+	defer close(done)
+	for range events {
+	}
+}
+
+// newRunID generates a run identifier for a fresh (non-resumed) execution.
+func newRunID() string {
+	return fmt.Sprintf("run-%d", time.Now().UnixNano())
+}
+
+// latestRunID finds the most recently updated checkpointed run for
+// workflowName, so `--resume` without an explicit `--run-id` picks up
+// where that workflow last left off instead of generating a fresh ID
+// (which, having no prior checkpoint, would silently behave like a
+// non-resumed run).
+func latestRunID(backend state.Backend, workflowName string) (string, error) {
+	runs, err := backend.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	var latest *state.Run
+	for _, run := range runs {
+		if run.WorkflowName != workflowName {
+			continue
+		}
+		if latest == nil || run.UpdatedAt.After(latest.UpdatedAt) {
+			latest = run
+		}
+	}
+	if latest == nil {
+		return "", fmt.Errorf("no checkpointed run found for workflow %q; pass --run-id explicitly", workflowName)
+	}
+	return latest.RunID, nil
+}
+
+// runWithShutdownTimeout runs the workflow in the background and waits
+// for it to finish. If ctx is canceled (SIGINT/SIGTERM), in-flight tasks
+// get up to --shutdown-timeout to wind down - engine.Run stops
+// scheduling new tasks as soon as it observes the cancellation - before
+// this gives up and returns whatever results are in hand.
+func runWithShutdownTimeout(ctx context.Context, engine *workflow.Engine, log *logger.Logger) ([]*types.TaskResult, error) {
+	type outcome struct {
+		results []*types.TaskResult
+		err     error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		results, err := engine.Run(ctx)
+		done <- outcome{results, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.results, o.err
+	case <-ctx.Done():
+		log.Warn("shutdown requested, waiting for in-flight tasks", logger.Fields{"timeout": shutdownTimeout.String()})
+		select {
+		case o := <-done:
+			return o.results, o.err
+		case <-time.After(shutdownTimeout):
+			results := engine.Results()
+			log.Error("shutdown timeout exceeded, returning partial results", logger.Fields{"tasks_completed": len(results)})
+			return results, ctx.Err()
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVar(&logFormat, "log-format", "text", "log output format: text or json")
+	runCmd.Flags().StringVar(&logLevel, "log-level", "info", "minimum log level: debug, info, warn, error")
+	runCmd.Flags().BoolVar(&resume, "resume", false, "resume a previous run instead of starting fresh (default --run-id: the most recently updated checkpoint for this workflow)")
+	runCmd.Flags().StringVar(&runID, "run-id", "", "run identifier to use or resume (default: generated when starting fresh, looked up when --resume is set)")
+	runCmd.Flags().IntVar(&maxProcs, "max-procs", 0, "max parallel tasks (default: workflow's max_parallel, or runtime.NumCPU())")
+	runCmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "how long to wait for in-flight tasks after SIGINT/SIGTERM before giving up")
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BaseMax/go-taskflow/pkg/logger"
+	"github.com/BaseMax/go-taskflow/pkg/types"
+	"github.com/BaseMax/go-taskflow/pkg/workflow"
+)
+
+// withShutdownTimeout sets the package-level shutdownTimeout for the
+// duration of the test and restores it afterward.
+func withShutdownTimeout(t *testing.T, d time.Duration) {
+	t.Helper()
+	orig := shutdownTimeout
+	shutdownTimeout = d
+	t.Cleanup(func() { shutdownTimeout = orig })
+}
+
+func TestRunWithShutdownTimeoutReturnsResultsOnNormalCompletion(t *testing.T) {
+	withShutdownTimeout(t, 30*time.Second)
+
+	wf := &types.Workflow{
+		Name:  "shutdown-normal-test",
+		Tasks: []types.Task{{Name: "a", Type: "shell", Command: "echo ok"}},
+	}
+	engine := workflow.NewEngine(wf)
+	log := logger.New(logger.LevelError, logger.FormatText, errWriter{t})
+
+	results, err := runWithShutdownTimeout(context.Background(), engine, log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("expected 1 successful result, got %+v", results)
+	}
+}
+
+func TestRunWithShutdownTimeoutReturnsPartialResultsOnTimeout(t *testing.T) {
+	// A short shutdown-timeout with a task that keeps running well past
+	// it simulates a plugin/command that doesn't wind down promptly.
+	withShutdownTimeout(t, 1*time.Millisecond)
+
+	wf := &types.Workflow{
+		Name:  "shutdown-timeout-test",
+		Tasks: []types.Task{{Name: "slow", Type: "shell", Command: "sleep 0.3"}},
+	}
+	engine := workflow.NewEngine(wf)
+	log := logger.New(logger.LevelError, logger.FormatText, errWriter{t})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	results, err := runWithShutdownTimeout(ctx, engine, log)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// Must return whatever engine.Results() had at the timeout instant,
+	// not nil - even if that happens to be empty here, the call must go
+	// through engine.Results() rather than discarding by returning early.
+	if results == nil {
+		t.Errorf("expected non-nil results from engine.Results(), got nil")
+	}
+
+	// The whole call should return close to ctx's deadline plus the tiny
+	// shutdown-timeout, not wait out the full 0.3s task.
+	if elapsed > 250*time.Millisecond {
+		t.Errorf("runWithShutdownTimeout took %v, expected it to give up near the shutdown timeout instead of waiting for the task", elapsed)
+	}
+}
+
+// errWriter discards log output in a test, routing anything written to
+// t.Log so failures still carry context.
+type errWriter struct{ t *testing.T }
+
+func (w errWriter) Write(p []byte) (int, error) {
+	w.t.Logf("%s", p)
+	return len(p), nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestLatestRunIDPicksMostRecentlyUpdated(t *testing.T) {
+	backend, err := state.NewBoltBackend(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("failed to open state backend: %v", err)
+	}
+	defer backend.Close()
+
+	older := &state.Run{WorkflowName: "wf", RunID: "run-old", Results: map[string]*types.TaskResult{}}
+	if err := backend.Save(older); err != nil {
+		t.Fatalf("failed to save older run: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	newer := &state.Run{WorkflowName: "wf", RunID: "run-new", Results: map[string]*types.TaskResult{}}
+	if err := backend.Save(newer); err != nil {
+		t.Fatalf("failed to save newer run: %v", err)
+	}
+
+	// A run under a different workflow name must not be picked.
+	other := &state.Run{WorkflowName: "other-wf", RunID: "run-other", Results: map[string]*types.TaskResult{}}
+	if err := backend.Save(other); err != nil {
+		t.Fatalf("failed to save other run: %v", err)
+	}
+
+	got, err := latestRunID(backend, "wf")
+	if err != nil {
+		t.Fatalf("latestRunID returned error: %v", err)
+	}
+	if got != "run-new" {
+		t.Errorf("latestRunID = %q, want %q", got, "run-new")
+	}
+}
+
+func TestLatestRunIDErrorsWhenNoneFound(t *testing.T) {
+	backend, err := state.NewBoltBackend(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("failed to open state backend: %v", err)
+	}
+	defer backend.Close()
+
+	if _, err := latestRunID(backend, "missing-wf"); err == nil {
+		t.Fatal("expected error when no checkpointed run exists, got nil")
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [run-id]",
+	Short: "Show the checkpointed results of a run",
+	Long:  `Print every task result recorded for a run, as of its last checkpoint.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		targetID := args[0]
+
+		backend, err := state.Open(stateBackend, stateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer backend.Close()
+
+		run := findRun(backend, targetID)
+		if run == nil {
+			fmt.Fprintf(os.Stderr, "Error: no run %q found\n", targetID)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Run:      %s\n", run.RunID)
+		fmt.Printf("Workflow: %s\n", run.WorkflowName)
+		fmt.Printf("Started:  %s\n", run.StartedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Updated:  %s\n", run.UpdatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Println()
+
+		for name, result := range run.Results {
+			status := "✅"
+			if !result.Success {
+				status = "❌"
+			}
+			fmt.Printf("%s %s\n", status, name)
+			if result.Error != nil {
+				fmt.Printf("   Error: %v\n", result.Error)
+			}
+		}
+	},
+}
+
+// findRun scans every run in backend for one matching runID, since the
+// state backend keys runs by workflow name + run ID and the workflow
+// name isn't known from a bare run ID alone.
+func findRun(backend state.Backend, runID string) *state.Run {
+	runs, err := backend.List()
+	if err != nil {
+		return nil
+	}
+	for _, run := range runs {
+		if run.RunID == runID {
+			return run
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BaseMax/go-taskflow/pkg/parser"
+	"github.com/BaseMax/go-taskflow/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [workflow-file]",
+	Short: "Statically validate a workflow without running it",
+	Long:  `Check a workflow's task graph and expressions: depends_on and condition must reference tasks that actually exist.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		workflowFile := args[0]
+
+		wf, err := parser.ParseWorkflowFile(workflowFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error parsing workflow: %v\n", err)
+			os.Exit(1)
+		}
+
+		problems := workflow.Validate(wf)
+		if len(problems) > 0 {
+			fmt.Fprintf(os.Stderr, "❌ %s has %d problem(s):\n", wf.Name, len(problems))
+			for _, problem := range problems {
+				fmt.Fprintf(os.Stderr, "   - %v\n", problem)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ %s is valid (%d tasks)\n", wf.Name, len(wf.Tasks))
+		for _, task := range wf.Tasks {
+			if task.Resources.CPU > 0 || task.Resources.MemoryMB > 0 {
+				fmt.Printf("⚠️  task %q sets resources.cpu/memory_mb, which taskflow records but does not enforce (only resources.concurrency_group is)\n", task.Name)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// DiscoveredTarget is a single live service instance returned by a
+// discovery provider.
+type DiscoveredTarget struct {
+	Host string
+	Port int
+}
+
+// Discover queries the service registry described by cfg and returns the
+// live targets found, used by the workflow engine to expand a "discovery"
+// task into one child task per target.
+func (e *Executor) Discover(ctx context.Context, cfg types.DiscoveryConfig) ([]DiscoveredTarget, error) {
+	switch cfg.Provider {
+	case "consul":
+		return e.discoverConsul(ctx, cfg)
+	case "dns":
+		return e.discoverDNS(ctx, cfg)
+	case "http":
+		return e.discoverHTTP(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown discovery provider: %s", cfg.Provider)
+	}
+}
+
+// consulCatalogEntry mirrors the fields taskflow needs from a Consul
+// /v1/catalog/service/<name> response entry.
+type consulCatalogEntry struct {
+	ServiceAddress string `json:"ServiceAddress"`
+	Address        string `json:"Address"`
+	ServicePort    int    `json:"ServicePort"`
+}
+
+// discoverConsul queries a Consul agent's service catalog for the
+// registered instances of cfg.Service; instances are not filtered by
+// health.
+func (e *Executor) discoverConsul(ctx context.Context, cfg types.DiscoveryConfig) ([]DiscoveredTarget, error) {
+	addr := cfg.ConsulAddr
+	if addr == "" {
+		addr = "http://127.0.0.1:8500"
+	}
+	url := fmt.Sprintf("%s/v1/catalog/service/%s", addr, cfg.Service)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build consul catalog request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul catalog request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("consul catalog request failed: status code %d", resp.StatusCode)
+	}
+
+	var entries []consulCatalogEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode consul catalog response: %w", err)
+	}
+
+	targets := make([]DiscoveredTarget, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.ServiceAddress
+		if host == "" {
+			host = entry.Address
+		}
+		targets = append(targets, DiscoveredTarget{Host: host, Port: entry.ServicePort})
+	}
+
+	return targets, nil
+}
+
+// discoverDNS resolves SRV records for cfg.DNSName to find live targets.
+func (e *Executor) discoverDNS(ctx context.Context, cfg types.DiscoveryConfig) ([]DiscoveredTarget, error) {
+	_, srvs, err := net.DefaultResolver.LookupSRV(ctx, "", "", cfg.DNSName)
+	if err != nil {
+		return nil, fmt.Errorf("SRV lookup for %s failed: %w", cfg.DNSName, err)
+	}
+
+	targets := make([]DiscoveredTarget, 0, len(srvs))
+	for _, srv := range srvs {
+		targets = append(targets, DiscoveredTarget{
+			Host: strings.TrimSuffix(srv.Target, "."),
+			Port: int(srv.Port),
+		})
+	}
+
+	return targets, nil
+}
+
+// httpDiscoveryTarget is the shape expected from a static HTTP discovery
+// endpoint: a JSON array of {"host": "...", "port": 1234}.
+type httpDiscoveryTarget struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// discoverHTTP fetches a static JSON endpoint listing live targets.
+func (e *Executor) discoverHTTP(ctx context.Context, cfg types.DiscoveryConfig) ([]DiscoveredTarget, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discovery request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discovery request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("discovery request failed: status code %d", resp.StatusCode)
+	}
+
+	var entries []httpDiscoveryTarget
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery response: %w", err)
+	}
+
+	targets := make([]DiscoveredTarget, 0, len(entries))
+	for _, entry := range entries {
+		targets = append(targets, DiscoveredTarget{Host: entry.Host, Port: entry.Port})
+	}
+
+	return targets, nil
+}
@@ -12,6 +12,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BaseMax/go-taskflow/pkg/executor/registry"
+	"github.com/BaseMax/go-taskflow/pkg/expr"
+	"github.com/BaseMax/go-taskflow/pkg/plugin"
 	"github.com/BaseMax/go-taskflow/pkg/types"
 )
 
@@ -26,13 +29,52 @@ const (
 // Executor handles execution of different task types
 type Executor struct {
 	variables map[string]string
+	registry  *registry.Registry
+
+	// taskResults, when set, supplies tasks.<name>.<field> values for
+	// ${...} templates (Command, URL, Body, Headers, FilePath,
+	// SourcePath, DestPath). The engine sets this via SetTaskResults so
+	// templates can see prior task output the same way conditions do
+	// through Engine.evaluateCondition.
+	taskResults func() map[string]expr.TaskContext
 }
 
-// NewExecutor creates a new task executor
+// NewExecutor creates a new task executor with the builtin shell, http,
+// file and discovery handlers registered.
 func NewExecutor(variables map[string]string) *Executor {
-	return &Executor{
+	e := &Executor{
 		variables: variables,
+		registry:  registry.New(),
 	}
+	e.registerBuiltins()
+	return e
+}
+
+// SetTaskResults installs a callback the executor queries on every
+// ${...} template render to populate tasks.<name>.<field> references.
+// provider is called fresh each time so it always sees the engine's
+// latest results.
+func (e *Executor) SetTaskResults(provider func() map[string]expr.TaskContext) {
+	e.taskResults = provider
+}
+
+// registerBuiltins wires the task types taskflow ships with into the
+// handler registry.
+func (e *Executor) registerBuiltins() {
+	e.registry.Register("shell", registry.HandlerFunc(e.executeShell))
+	e.registry.Register("http", registry.HandlerFunc(e.executeHTTP))
+	e.registry.Register("file", registry.HandlerFunc(e.executeFile))
+	e.registry.Register("discovery", registry.HandlerFunc(func(ctx context.Context, task types.Task) (string, error) {
+		return "", fmt.Errorf("discovery tasks are expanded by the workflow engine and cannot be executed directly")
+	}))
+}
+
+// RegisterPlugin routes any task whose type is name to the out-of-process
+// plugin p, overriding a builtin handler of the same name if one exists.
+func (e *Executor) RegisterPlugin(name string, p *plugin.Plugin) {
+	e.registry.Register(name, registry.HandlerFunc(func(ctx context.Context, task types.Task) (string, error) {
+		return p.Call(ctx, task)
+	}))
 }
 
 // Execute executes a task based on its type
@@ -42,19 +84,7 @@ func (e *Executor) Execute(ctx context.Context, task types.Task) (*types.TaskRes
 		StartTime: time.Now(),
 	}
 
-	var err error
-	var output string
-
-	switch task.Type {
-	case "shell":
-		output, err = e.executeShell(ctx, task)
-	case "http":
-		output, err = e.executeHTTP(ctx, task)
-	case "file":
-		output, err = e.executeFile(ctx, task)
-	default:
-		err = fmt.Errorf("unknown task type: %s", task.Type)
-	}
+	output, err := e.registry.Dispatch(ctx, task)
 
 	result.EndTime = time.Now()
 	result.Output = output
@@ -72,7 +102,7 @@ func (e *Executor) executeShell(ctx context.Context, task types.Task) (string, e
 	}
 
 	// Replace variables in command
-	command = e.replaceVariables(command)
+	command = e.replaceVariables(command, task.Vars)
 
 	// Use appropriate shell based on OS
 	var cmd *exec.Cmd
@@ -95,7 +125,7 @@ func (e *Executor) executeShell(ctx context.Context, task types.Task) (string, e
 
 // executeHTTP executes an HTTP request
 func (e *Executor) executeHTTP(ctx context.Context, task types.Task) (string, error) {
-	url := e.replaceVariables(task.URL)
+	url := e.replaceVariables(task.URL, task.Vars)
 	method := task.Method
 	if method == "" {
 		method = "GET"
@@ -103,7 +133,7 @@ func (e *Executor) executeHTTP(ctx context.Context, task types.Task) (string, er
 
 	var body io.Reader
 	if task.Body != "" {
-		body = strings.NewReader(e.replaceVariables(task.Body))
+		body = strings.NewReader(e.replaceVariables(task.Body, task.Vars))
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
@@ -113,7 +143,7 @@ func (e *Executor) executeHTTP(ctx context.Context, task types.Task) (string, er
 
 	// Add headers
 	for key, value := range task.Headers {
-		req.Header.Set(key, e.replaceVariables(value))
+		req.Header.Set(key, e.replaceVariables(value, task.Vars))
 	}
 
 	client := &http.Client{}
@@ -153,7 +183,7 @@ func (e *Executor) executeFile(ctx context.Context, task types.Task) (string, er
 
 // fileRead reads content from a file
 func (e *Executor) fileRead(task types.Task) (string, error) {
-	filePath := e.replaceVariables(task.FilePath)
+	filePath := e.replaceVariables(task.FilePath, task.Vars)
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
@@ -163,8 +193,8 @@ func (e *Executor) fileRead(task types.Task) (string, error) {
 
 // fileWrite writes content to a file
 func (e *Executor) fileWrite(task types.Task) (string, error) {
-	filePath := e.replaceVariables(task.FilePath)
-	content := e.replaceVariables(task.FileContent)
+	filePath := e.replaceVariables(task.FilePath, task.Vars)
+	content := e.replaceVariables(task.FileContent, task.Vars)
 
 	err := os.WriteFile(filePath, []byte(content), 0644)
 	if err != nil {
@@ -175,7 +205,7 @@ func (e *Executor) fileWrite(task types.Task) (string, error) {
 
 // fileDelete deletes a file
 func (e *Executor) fileDelete(task types.Task) (string, error) {
-	filePath := e.replaceVariables(task.FilePath)
+	filePath := e.replaceVariables(task.FilePath, task.Vars)
 	err := os.Remove(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to delete file: %w", err)
@@ -185,8 +215,8 @@ func (e *Executor) fileDelete(task types.Task) (string, error) {
 
 // fileCopy copies a file
 func (e *Executor) fileCopy(task types.Task) (string, error) {
-	sourcePath := e.replaceVariables(task.SourcePath)
-	destPath := e.replaceVariables(task.DestPath)
+	sourcePath := e.replaceVariables(task.SourcePath, task.Vars)
+	destPath := e.replaceVariables(task.DestPath, task.Vars)
 
 	sourceData, err := os.ReadFile(sourcePath)
 	if err != nil {
@@ -201,11 +231,35 @@ func (e *Executor) fileCopy(task types.Task) (string, error) {
 	return fmt.Sprintf("Successfully copied %s to %s", sourcePath, destPath), nil
 }
 
-// replaceVariables replaces ${VAR} patterns with actual variable values
-func (e *Executor) replaceVariables(input string) string {
-	result := input
-	for key, value := range e.variables {
-		result = strings.ReplaceAll(result, "${"+key+"}", value)
+// replaceVariables renders ${...} expressions against the workflow's
+// variables and, when SetTaskResults has been called, prior tasks'
+// results (tasks.<name>.output/success/duration/json). overrides
+// (typically a task's per-instance Vars, e.g. ${discovered.host}) take
+// precedence over the workflow-level variables. A template that fails
+// to evaluate (e.g. references an unknown name) is returned unchanged
+// rather than aborting the task.
+func (e *Executor) replaceVariables(input string, overrides map[string]string) string {
+	ctx := expr.Context{Vars: mergeVarMaps(e.variables, overrides), Env: expr.EnvironMap()}
+	if e.taskResults != nil {
+		ctx.Tasks = e.taskResults()
+	}
+
+	rendered, err := expr.Render(input, ctx)
+	if err != nil {
+		return input
+	}
+	return rendered
+}
+
+// mergeVarMaps returns a new map containing base with overrides applied
+// on top.
+func mergeVarMaps(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
 	}
-	return result
+	return merged
 }
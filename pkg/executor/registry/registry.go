@@ -0,0 +1,62 @@
+// Package registry maps task types to the TaskHandler responsible for
+// executing them, so the executor can dispatch to builtin handlers or
+// out-of-process plugins without a hardcoded type switch.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// TaskHandler executes a single task and returns its output.
+type TaskHandler interface {
+	Handle(ctx context.Context, task types.Task) (string, error)
+}
+
+// HandlerFunc adapts a plain function to the TaskHandler interface.
+type HandlerFunc func(ctx context.Context, task types.Task) (string, error)
+
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx context.Context, task types.Task) (string, error) {
+	return f(ctx, task)
+}
+
+// Registry maps task types to the handler that executes them.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]TaskHandler
+}
+
+// New creates an empty handler registry.
+func New() *Registry {
+	return &Registry{handlers: make(map[string]TaskHandler)}
+}
+
+// Register associates taskType with handler, overwriting any existing
+// registration so plugins can replace builtin handlers if needed.
+func (r *Registry) Register(taskType string, handler TaskHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[taskType] = handler
+}
+
+// Lookup returns the handler registered for taskType, if any.
+func (r *Registry) Lookup(taskType string) (TaskHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	handler, ok := r.handlers[taskType]
+	return handler, ok
+}
+
+// Dispatch looks up the handler for task.Type and runs it, returning an
+// error if no handler is registered for that type.
+func (r *Registry) Dispatch(ctx context.Context, task types.Task) (string, error) {
+	handler, ok := r.Lookup(task.Type)
+	if !ok {
+		return "", fmt.Errorf("unknown task type: %s", task.Type)
+	}
+	return handler.Handle(ctx, task)
+}
@@ -0,0 +1,35 @@
+package expr
+
+// Node is a parsed expression AST node.
+type Node interface{}
+
+// NumberLit is a numeric literal, e.g. 42 or 3.5.
+type NumberLit struct{ Value float64 }
+
+// StringLit is a quoted string literal.
+type StringLit struct{ Value string }
+
+// BoolLit is the literal true or false.
+type BoolLit struct{ Value bool }
+
+// Ident is a dotted identifier path, e.g. tasks.build.output or env.HOME.
+type Ident struct{ Path []string }
+
+// UnaryExpr is a prefix operator applied to X, e.g. !ok or -1.
+type UnaryExpr struct {
+	Op string
+	X  Node
+}
+
+// BinaryExpr is an infix operator applied to X and Y.
+type BinaryExpr struct {
+	Op   string
+	X, Y Node
+}
+
+// CallExpr is a call to one of the builtin string functions, e.g.
+// contains(tasks.build.output, "ok").
+type CallExpr struct {
+	Func string
+	Args []Node
+}
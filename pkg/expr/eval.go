@@ -0,0 +1,344 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaskContext is the subset of a task's result visible to expressions as
+// tasks.<name>.<field>.
+type TaskContext struct {
+	Output     string
+	Success    bool
+	DurationMS int64
+	// JSON is the parsed form of Output when it happens to be valid JSON,
+	// enabling tasks.<name>.json.<field> access. Nil if Output isn't JSON.
+	JSON interface{}
+}
+
+// Context supplies the values an expression can reference: prior task
+// results, process environment, and workflow variables.
+type Context struct {
+	Tasks map[string]TaskContext
+	Env   map[string]string
+	Vars  map[string]string
+}
+
+// Eval parses and evaluates an expression against ctx.
+func Eval(src string, ctx Context) (interface{}, error) {
+	node, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return evalNode(node, ctx)
+}
+
+func evalNode(node Node, ctx Context) (interface{}, error) {
+	switch n := node.(type) {
+	case *NumberLit:
+		return n.Value, nil
+	case *StringLit:
+		return n.Value, nil
+	case *BoolLit:
+		return n.Value, nil
+	case *Ident:
+		return resolveIdent(n.Path, ctx)
+	case *UnaryExpr:
+		return evalUnary(n, ctx)
+	case *BinaryExpr:
+		return evalBinary(n, ctx)
+	case *CallExpr:
+		return evalCall(n, ctx)
+	default:
+		return nil, fmt.Errorf("unhandled expression node %T", node)
+	}
+}
+
+func evalUnary(n *UnaryExpr, ctx Context) (interface{}, error) {
+	x, err := evalNode(n.X, ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch n.Op {
+	case "!":
+		return !Truthy(x), nil
+	case "-":
+		num, ok := asNumber(x)
+		if !ok {
+			return nil, fmt.Errorf("unary - requires a number, got %T", x)
+		}
+		return -num, nil
+	default:
+		return nil, fmt.Errorf("unknown unary operator %q", n.Op)
+	}
+}
+
+func evalBinary(n *BinaryExpr, ctx Context) (interface{}, error) {
+	// && and || short-circuit, so Y is only evaluated when needed.
+	if n.Op == "&&" {
+		x, err := evalNode(n.X, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !Truthy(x) {
+			return false, nil
+		}
+		y, err := evalNode(n.Y, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return Truthy(y), nil
+	}
+	if n.Op == "||" {
+		x, err := evalNode(n.X, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if Truthy(x) {
+			return true, nil
+		}
+		y, err := evalNode(n.Y, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return Truthy(y), nil
+	}
+
+	x, err := evalNode(n.X, ctx)
+	if err != nil {
+		return nil, err
+	}
+	y, err := evalNode(n.Y, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return compareValues(x, y, n.Op)
+	case "+", "-", "*", "/":
+		return evalArith(n.Op, x, y)
+	default:
+		return nil, fmt.Errorf("unknown binary operator %q", n.Op)
+	}
+}
+
+func evalCall(n *CallExpr, ctx Context) (interface{}, error) {
+	args := make([]interface{}, len(n.Args))
+	for i, argNode := range n.Args {
+		v, err := evalNode(argNode, ctx)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	switch n.Func {
+	case "contains":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("contains() takes 2 arguments, got %d", len(args))
+		}
+		return strings.Contains(toString(args[0]), toString(args[1])), nil
+	case "hasPrefix":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("hasPrefix() takes 2 arguments, got %d", len(args))
+		}
+		return strings.HasPrefix(toString(args[0]), toString(args[1])), nil
+	case "hasSuffix":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("hasSuffix() takes 2 arguments, got %d", len(args))
+		}
+		return strings.HasSuffix(toString(args[0]), toString(args[1])), nil
+	default:
+		return nil, fmt.Errorf("unknown function %q", n.Func)
+	}
+}
+
+func resolveIdent(path []string, ctx Context) (interface{}, error) {
+	switch path[0] {
+	case "tasks":
+		if len(path) < 3 {
+			return nil, fmt.Errorf("tasks reference must be tasks.<name>.<field>, got %q", strings.Join(path, "."))
+		}
+		tc, ok := ctx.Tasks[path[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown task %q in expression", path[1])
+		}
+		switch path[2] {
+		case "output":
+			return tc.Output, nil
+		case "success":
+			return tc.Success, nil
+		case "duration":
+			return float64(tc.DurationMS), nil
+		case "json":
+			return navigateJSON(tc.JSON, path[3:])
+		default:
+			return nil, fmt.Errorf("unknown field %q on tasks.%s", path[2], path[1])
+		}
+	case "env":
+		if len(path) != 2 {
+			return nil, fmt.Errorf("env reference must be env.<NAME>, got %q", strings.Join(path, "."))
+		}
+		return ctx.Env[path[1]], nil
+	case "vars":
+		if len(path) != 2 {
+			return nil, fmt.Errorf("vars reference must be vars.<name>, got %q", strings.Join(path, "."))
+		}
+		return ctx.Vars[path[1]], nil
+	default:
+		// Anything else is looked up directly against Vars under its full
+		// dotted name, e.g. ${REGION} or the ${discovered.host} /
+		// ${matrix.region} scopes the engine generates per task instance.
+		key := strings.Join(path, ".")
+		if v, ok := ctx.Vars[key]; ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q", key)
+	}
+}
+
+// navigateJSON walks a decoded JSON value (map[string]interface{} nesting)
+// following path, as used by tasks.<name>.json.<a>.<b>.
+func navigateJSON(value interface{}, path []string) (interface{}, error) {
+	cur := value
+	for _, key := range path {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot access field %q: not a JSON object", key)
+		}
+		cur = obj[key]
+	}
+	return cur, nil
+}
+
+// Truthy reports whether v should be treated as true in a boolean context:
+// nil, false, 0, "" and the strings "false" and "0" are false; any other
+// value is true.
+func Truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != "" && x != "false" && x != "0"
+	case float64:
+		return x != 0
+	default:
+		return true
+	}
+}
+
+func asNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
+
+func compareValues(x, y interface{}, op string) (interface{}, error) {
+	if xn, xok := asNumber(x); xok {
+		if yn, yok := asNumber(y); yok {
+			switch op {
+			case "==":
+				return xn == yn, nil
+			case "!=":
+				return xn != yn, nil
+			case "<":
+				return xn < yn, nil
+			case "<=":
+				return xn <= yn, nil
+			case ">":
+				return xn > yn, nil
+			case ">=":
+				return xn >= yn, nil
+			}
+		}
+	}
+
+	if xb, ok := x.(bool); ok {
+		if yb, ok := y.(bool); ok {
+			switch op {
+			case "==":
+				return xb == yb, nil
+			case "!=":
+				return xb != yb, nil
+			}
+		}
+	}
+
+	xs, ys := toString(x), toString(y)
+	switch op {
+	case "==":
+		return xs == ys, nil
+	case "!=":
+		return xs != ys, nil
+	case "<":
+		return xs < ys, nil
+	case "<=":
+		return xs <= ys, nil
+	case ">":
+		return xs > ys, nil
+	case ">=":
+		return xs >= ys, nil
+	default:
+		return nil, fmt.Errorf("unknown comparison operator %q", op)
+	}
+}
+
+func evalArith(op string, x, y interface{}) (interface{}, error) {
+	if op == "+" {
+		if xs, ok := x.(string); ok {
+			return xs + toString(y), nil
+		}
+		if ys, ok := y.(string); ok {
+			return toString(x) + ys, nil
+		}
+	}
+
+	xn, xok := asNumber(x)
+	yn, yok := asNumber(y)
+	if !xok || !yok {
+		return nil, fmt.Errorf("%q requires numeric operands, got %T and %T", op, x, y)
+	}
+
+	switch op {
+	case "+":
+		return xn + yn, nil
+	case "-":
+		return xn - yn, nil
+	case "*":
+		return xn * yn, nil
+	case "/":
+		if yn == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return xn / yn, nil
+	default:
+		return nil, fmt.Errorf("unknown arithmetic operator %q", op)
+	}
+}
@@ -0,0 +1,165 @@
+package expr
+
+import "testing"
+
+func TestEvalArithmeticPrecedence(t *testing.T) {
+	cases := []struct {
+		src  string
+		want float64
+	}{
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 2 - 3", 5},
+		{"2 * 3 + 4 * 5", 26},
+		{"-2 + 3", 1},
+		{"10 / 2 / 5", 1},
+	}
+	for _, c := range cases {
+		got, err := Eval(c.src, Context{})
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", c.src, err)
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestEvalComparisonAndLogicalPrecedence(t *testing.T) {
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{"1 + 1 == 2", true},
+		{"1 < 2 && 2 < 3", true},
+		{"1 < 2 && 2 > 3", false},
+		{"1 > 2 || 3 > 2", true},
+		{"!(1 == 2)", true},
+		{"!true && false", false},
+		{"1 == 1 || 1 == 2 && 1 == 3", true}, // && binds tighter than ||
+	}
+	for _, c := range cases {
+		got, err := Eval(c.src, Context{})
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", c.src, err)
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestEvalShortCircuit(t *testing.T) {
+	// An unknown identifier on the side that should never evaluate must
+	// not surface an error.
+	got, err := Eval("false && undefined_ident", Context{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != false {
+		t.Errorf("got %v, want false", got)
+	}
+
+	got, err = Eval("true || undefined_ident", Context{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != true {
+		t.Errorf("got %v, want true", got)
+	}
+}
+
+func TestEvalStringFunctions(t *testing.T) {
+	cases := []struct {
+		src  string
+		want bool
+	}{
+		{`contains("hello world", "world")`, true},
+		{`contains("hello world", "bye")`, false},
+		{`hasPrefix("hello", "he")`, true},
+		{`hasSuffix("hello", "lo")`, true},
+		{`hasSuffix("hello", "xx")`, false},
+	}
+	for _, c := range cases {
+		got, err := Eval(c.src, Context{})
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", c.src, err)
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestEvalIdentResolution(t *testing.T) {
+	ctx := Context{
+		Tasks: map[string]TaskContext{
+			"build": {
+				Output:     "ok",
+				Success:    true,
+				DurationMS: 1500,
+				JSON:       map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+			},
+		},
+		Env:  map[string]string{"HOME": "/root"},
+		Vars: map[string]string{"region": "us-east", "discovered.host": "10.0.0.1"},
+	}
+
+	cases := []struct {
+		src  string
+		want interface{}
+	}{
+		{"tasks.build.output", "ok"},
+		{"tasks.build.success", true},
+		{"tasks.build.duration", 1500.0},
+		{"env.HOME", "/root"},
+		{"vars.region", "us-east"},
+		{"region", "us-east"},
+		{"discovered.host", "10.0.0.1"},
+	}
+	for _, c := range cases {
+		got, err := Eval(c.src, ctx)
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", c.src, err)
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %v, want %v", c.src, got, c.want)
+		}
+	}
+}
+
+func TestEvalUnknownIdentifierErrors(t *testing.T) {
+	_, err := Eval("nope", Context{})
+	if err == nil {
+		t.Fatal("expected error for unknown identifier, got nil")
+	}
+}
+
+func TestEvalUnknownTaskErrors(t *testing.T) {
+	_, err := Eval("tasks.missing.output", Context{})
+	if err == nil {
+		t.Fatal("expected error for unknown task, got nil")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"1 +",
+		"(1 + 2",
+		"1 2",
+		"1 @ 2",
+		`"unterminated`,
+		"unknown_func(1, 2)",
+	}
+	for _, src := range cases {
+		if _, err := Eval(src, Context{}); err == nil {
+			t.Errorf("Eval(%q) expected error, got none", src)
+		}
+	}
+}
+
+func TestEvalDivisionByZero(t *testing.T) {
+	if _, err := Eval("1 / 0", Context{}); err == nil {
+		t.Fatal("expected division by zero error, got nil")
+	}
+}
@@ -0,0 +1,84 @@
+package expr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvironMap snapshots os.Environ() as a map, used to populate
+// Context.Env for env.<NAME> access.
+func EnvironMap() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// Render replaces every ${...} placeholder in template with the result of
+// evaluating its contents as an expression against ctx, e.g.
+// "${tasks.build.output}" or "${vars.region}-${1+1}".
+func Render(template string, ctx Context) (string, error) {
+	var b strings.Builder
+	i := 0
+	for i < len(template) {
+		start := strings.Index(template[i:], "${")
+		if start == -1 {
+			b.WriteString(template[i:])
+			break
+		}
+		start += i
+		b.WriteString(template[i:start])
+
+		end := strings.Index(template[start:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated ${...} in template starting at %q", template[start:])
+		}
+		end += start
+
+		src := template[start+2 : end]
+		val, err := Eval(src, ctx)
+		if err != nil {
+			return "", fmt.Errorf("evaluating %q: %w", src, err)
+		}
+		b.WriteString(toString(val))
+
+		i = end + 1
+	}
+	return b.String(), nil
+}
+
+// CollectTaskRefs returns every distinct task name referenced via
+// tasks.<name>... in node, used by `taskflow validate` to statically
+// check conditions against the declared task graph.
+func CollectTaskRefs(node Node) []string {
+	seen := make(map[string]bool)
+	collectTaskRefs(node, seen)
+
+	refs := make([]string, 0, len(seen))
+	for name := range seen {
+		refs = append(refs, name)
+	}
+	return refs
+}
+
+func collectTaskRefs(node Node, seen map[string]bool) {
+	switch n := node.(type) {
+	case *Ident:
+		if len(n.Path) >= 2 && n.Path[0] == "tasks" {
+			seen[n.Path[1]] = true
+		}
+	case *UnaryExpr:
+		collectTaskRefs(n.X, seen)
+	case *BinaryExpr:
+		collectTaskRefs(n.X, seen)
+		collectTaskRefs(n.Y, seen)
+	case *CallExpr:
+		for _, arg := range n.Args {
+			collectTaskRefs(arg, seen)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// lexer turns an expression's source text into a stream of tokens.
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src)}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) advance() rune {
+	r := l.peekRune()
+	l.pos++
+	return r
+}
+
+func (l *lexer) skipSpace() {
+	for unicode.IsSpace(l.peekRune()) {
+		l.pos++
+	}
+}
+
+// next returns the next token in the source, or a tokEOF token once
+// exhausted.
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+
+	r := l.peekRune()
+	switch {
+	case r == 0:
+		return token{kind: tokEOF}, nil
+	case r == '(':
+		l.advance()
+		return token{kind: tokLParen, text: "("}, nil
+	case r == ')':
+		l.advance()
+		return token{kind: tokRParen, text: ")"}, nil
+	case r == ',':
+		l.advance()
+		return token{kind: tokComma, text: ","}, nil
+	case r == '.':
+		l.advance()
+		return token{kind: tokDot, text: "."}, nil
+	case r == '+':
+		l.advance()
+		return token{kind: tokPlus, text: "+"}, nil
+	case r == '-':
+		l.advance()
+		return token{kind: tokMinus, text: "-"}, nil
+	case r == '*':
+		l.advance()
+		return token{kind: tokStar, text: "*"}, nil
+	case r == '/':
+		l.advance()
+		return token{kind: tokSlash, text: "/"}, nil
+	case r == '&':
+		l.advance()
+		if l.peekRune() != '&' {
+			return token{}, fmt.Errorf("unexpected character %q, did you mean &&?", r)
+		}
+		l.advance()
+		return token{kind: tokAnd, text: "&&"}, nil
+	case r == '|':
+		l.advance()
+		if l.peekRune() != '|' {
+			return token{}, fmt.Errorf("unexpected character %q, did you mean ||?", r)
+		}
+		l.advance()
+		return token{kind: tokOr, text: "||"}, nil
+	case r == '=':
+		l.advance()
+		if l.peekRune() != '=' {
+			return token{}, fmt.Errorf("unexpected character %q, did you mean ==?", r)
+		}
+		l.advance()
+		return token{kind: tokEq, text: "=="}, nil
+	case r == '!':
+		l.advance()
+		if l.peekRune() == '=' {
+			l.advance()
+			return token{kind: tokNeq, text: "!="}, nil
+		}
+		return token{kind: tokNot, text: "!"}, nil
+	case r == '<':
+		l.advance()
+		if l.peekRune() == '=' {
+			l.advance()
+			return token{kind: tokLte, text: "<="}, nil
+		}
+		return token{kind: tokLt, text: "<"}, nil
+	case r == '>':
+		l.advance()
+		if l.peekRune() == '=' {
+			l.advance()
+			return token{kind: tokGte, text: ">="}, nil
+		}
+		return token{kind: tokGt, text: ">"}, nil
+	case r == '"' || r == '\'':
+		return l.lexString(r)
+	case unicode.IsDigit(r):
+		return l.lexNumber()
+	case unicode.IsLetter(r) || r == '_':
+		return l.lexIdent()
+	default:
+		return token{}, fmt.Errorf("unexpected character %q in expression", r)
+	}
+}
+
+func (l *lexer) lexString(quote rune) (token, error) {
+	l.advance() // opening quote
+	var b strings.Builder
+	for {
+		r := l.advance()
+		if r == 0 {
+			return token{}, fmt.Errorf("unterminated string literal")
+		}
+		if r == quote {
+			break
+		}
+		if r == '\\' {
+			b.WriteRune(l.advance())
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return token{kind: tokString, text: b.String()}, nil
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	var b strings.Builder
+	for unicode.IsDigit(l.peekRune()) {
+		b.WriteRune(l.advance())
+	}
+	if l.peekRune() == '.' {
+		b.WriteRune(l.advance())
+		for unicode.IsDigit(l.peekRune()) {
+			b.WriteRune(l.advance())
+		}
+	}
+	return token{kind: tokNumber, text: b.String()}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	var b strings.Builder
+	for unicode.IsLetter(l.peekRune()) || unicode.IsDigit(l.peekRune()) || l.peekRune() == '_' {
+		b.WriteRune(l.advance())
+	}
+	return token{kind: tokIdent, text: b.String()}, nil
+}
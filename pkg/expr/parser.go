@@ -0,0 +1,240 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parser is a recursive-descent parser over a pre-tokenized expression.
+// Precedence, low to high: || , && , ==/!= , </<=/>/>= , +/- , */ , unary (! and -), primary.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse compiles an expression's source text into an AST.
+func Parse(src string) (Node, error) {
+	lx := newLexer(src)
+	var tokens []token
+	for {
+		tok, err := lx.next()
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", src, err)
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokEOF {
+			break
+		}
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", src, err)
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("invalid expression %q: unexpected token %q", src, p.peek().text)
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	if p.peek().kind != kind {
+		return token{}, fmt.Errorf("expected %s, got %q", what, p.peek().text)
+	}
+	return p.advance(), nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.advance()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "||", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	x, err := p.parseEquality()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.advance()
+		y, err := p.parseEquality()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "&&", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseEquality() (Node, error) {
+	x, err := p.parseRelational()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokEq || p.peek().kind == tokNeq {
+		op := p.advance()
+		y, err := p.parseRelational()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseRelational() (Node, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokLt || p.peek().kind == tokLte || p.peek().kind == tokGt || p.peek().kind == tokGte {
+		op := p.advance()
+		y, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAdditive() (Node, error) {
+	x, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokPlus || p.peek().kind == tokMinus {
+		op := p.advance()
+		y, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseMultiplicative() (Node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokStar || p.peek().kind == tokSlash {
+		op := p.advance()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokNot || p.peek().kind == tokMinus {
+		op := p.advance()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: op.text, X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNumber:
+		p.advance()
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return &NumberLit{Value: v}, nil
+	case tokString:
+		p.advance()
+		return &StringLit{Value: tok.text}, nil
+	case tokLParen:
+		p.advance()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case tokIdent:
+		return p.parseIdentOrCall()
+	default:
+		return nil, fmt.Errorf("unexpected token %q", tok.text)
+	}
+}
+
+func (p *parser) parseIdentOrCall() (Node, error) {
+	first := p.advance()
+
+	if first.text == "true" {
+		return &BoolLit{Value: true}, nil
+	}
+	if first.text == "false" {
+		return &BoolLit{Value: false}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.advance()
+		var args []Node
+		if p.peek().kind != tokRParen {
+			for {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.advance()
+			}
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return &CallExpr{Func: first.text, Args: args}, nil
+	}
+
+	path := []string{first.text}
+	for p.peek().kind == tokDot {
+		p.advance()
+		seg, err := p.expect(tokIdent, "identifier after '.'")
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, seg.text)
+	}
+	return &Ident{Path: path}, nil
+}
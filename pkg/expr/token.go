@@ -0,0 +1,34 @@
+package expr
+
+// tokenKind identifies the lexical category of a token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokAnd   // &&
+	tokOr    // ||
+	tokNot   // !
+	tokEq    // ==
+	tokNeq   // !=
+	tokLt    // <
+	tokLte   // <=
+	tokGt    // >
+	tokGte   // >=
+	tokPlus  // +
+	tokMinus // -
+	tokStar  // *
+	tokSlash // /
+	tokLParen
+	tokRParen
+	tokComma
+	tokDot
+)
+
+// token is a single lexical token with its literal text.
+type token struct {
+	kind tokenKind
+	text string
+}
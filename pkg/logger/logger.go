@@ -0,0 +1,140 @@
+// Package logger provides a small leveled, structured logger used in
+// place of ad-hoc fmt.Printf calls, so taskflow's output can be consumed
+// by both humans (text) and machines (json) in CI.
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is a logging severity.
+type Level int
+
+// Severity levels, low to high.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// ParseLevel maps a CLI-friendly name to a Level, defaulting to
+// LevelInfo for anything unrecognized.
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Format selects how log lines are rendered.
+type Format string
+
+// Supported output formats.
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Fields is a set of structured key/value pairs attached to a log line,
+// e.g. {"task": "build", "attempt": 2, "duration_ms": 134}.
+type Fields map[string]interface{}
+
+// Logger writes leveled, structured log lines to an io.Writer.
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  Level
+	format Format
+}
+
+// New creates a Logger that writes to out, filtering out anything below
+// level and rendering lines as format.
+func New(level Level, format Format, out io.Writer) *Logger {
+	return &Logger{out: out, level: level, format: format}
+}
+
+// Debug logs msg at LevelDebug.
+func (l *Logger) Debug(msg string, fields Fields) { l.log(LevelDebug, msg, fields) }
+
+// Info logs msg at LevelInfo.
+func (l *Logger) Info(msg string, fields Fields) { l.log(LevelInfo, msg, fields) }
+
+// Warn logs msg at LevelWarn.
+func (l *Logger) Warn(msg string, fields Fields) { l.log(LevelWarn, msg, fields) }
+
+// Error logs msg at LevelError.
+func (l *Logger) Error(msg string, fields Fields) { l.log(LevelError, msg, fields) }
+
+func (l *Logger) log(level Level, msg string, fields Fields) {
+	if level < l.level {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.format == FormatJSON {
+		l.writeJSON(level, msg, fields)
+		return
+	}
+	l.writeText(level, msg, fields)
+}
+
+func (l *Logger) writeText(level Level, msg string, fields Fields) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s level=%s msg=%q", time.Now().Format(time.RFC3339), level, msg)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+
+	fmt.Fprintln(l.out, b.String())
+}
+
+func (l *Logger) writeJSON(level Level, msg string, fields Fields) {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		entry[k] = v
+	}
+	entry["time"] = time.Now().Format(time.RFC3339)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Fprintf(l.out, `{"level":"error","msg":"failed to marshal log entry: %v"}`+"\n", err)
+		return
+	}
+	l.out.Write(append(data, '\n'))
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]Level{
+		"debug":   LevelDebug,
+		"info":    LevelInfo,
+		"warn":    LevelWarn,
+		"warning": LevelWarn,
+		"error":   LevelError,
+		"":        LevelInfo,
+		"bogus":   LevelInfo,
+	}
+	for s, want := range cases {
+		if got := ParseLevel(s); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestLogFiltersBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LevelWarn, FormatText, &buf)
+
+	l.Debug("debug msg", nil)
+	l.Info("info msg", nil)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output below configured level, got %q", buf.String())
+	}
+
+	l.Warn("warn msg", nil)
+	if !strings.Contains(buf.String(), "warn msg") {
+		t.Errorf("expected warn msg to be logged, got %q", buf.String())
+	}
+}
+
+func TestLogTextFormatIncludesSortedFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LevelInfo, FormatText, &buf)
+
+	l.Info("task finished", Fields{"task": "build", "attempt": 2})
+
+	line := buf.String()
+	if !strings.Contains(line, `msg="task finished"`) {
+		t.Errorf("expected quoted msg field, got %q", line)
+	}
+	if !strings.Contains(line, "attempt=2") || !strings.Contains(line, "task=build") {
+		t.Errorf("expected both fields present, got %q", line)
+	}
+	// Fields are sorted by key: attempt before task.
+	if strings.Index(line, "attempt=2") > strings.Index(line, "task=build") {
+		t.Errorf("expected fields in sorted key order, got %q", line)
+	}
+}
+
+func TestLogJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LevelInfo, FormatJSON, &buf)
+
+	l.Error("task failed", Fields{"task": "build"})
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode JSON log line: %v\nline: %s", err, buf.String())
+	}
+	if entry["msg"] != "task failed" {
+		t.Errorf("msg = %v, want %q", entry["msg"], "task failed")
+	}
+	if entry["level"] != "error" {
+		t.Errorf("level = %v, want %q", entry["level"], "error")
+	}
+	if entry["task"] != "build" {
+		t.Errorf("task = %v, want %q", entry["task"], "build")
+	}
+}
@@ -0,0 +1,165 @@
+// Package plugin implements the client side of taskflow's out-of-process
+// task handlers: a single JSON-RPC 2.0 request/response round-trip per
+// task, sent either to a subprocess over stdio or to a process already
+// listening on a Unix socket.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// Config describes how to reach a single plugin.
+type Config struct {
+	Name    string // plugin name, matched against a task's `type`
+	Command string // binary (with args) spawned over stdio, e.g. "taskflow-plugin-docker"
+	Socket  string // Unix socket to dial instead of spawning Command
+}
+
+// Plugin is a handle to an out-of-process task handler.
+type Plugin struct {
+	cfg Config
+}
+
+// New creates a plugin handle from cfg. Nothing is spawned or dialed
+// until Call is invoked.
+func New(cfg Config) *Plugin {
+	return &Plugin{cfg: cfg}
+}
+
+// rpcRequest is the JSON-RPC 2.0 envelope sent to a plugin. Params is the
+// task definition as declared in the workflow YAML.
+type rpcRequest struct {
+	JSONRPC string     `json:"jsonrpc"`
+	ID      int        `json:"id"`
+	Method  string     `json:"method"`
+	Params  types.Task `json:"params"`
+}
+
+// rpcResult is the plugin's {output, error} response payload.
+type rpcResult struct {
+	Output string `json:"output"`
+	Error  string `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string     `json:"jsonrpc"`
+	ID      int        `json:"id"`
+	Result  *rpcResult `json:"result,omitempty"`
+	Error   *rpcError  `json:"error,omitempty"`
+}
+
+// Call sends task to the plugin as a single "execute" RPC and returns the
+// output it reports.
+func (p *Plugin) Call(ctx context.Context, task types.Task) (string, error) {
+	payload, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: "execute", Params: task})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	var raw []byte
+	if p.cfg.Socket != "" {
+		raw, err = p.callSocket(ctx, payload)
+	} else {
+		raw, err = p.callStdio(ctx, payload)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var resp rpcResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", fmt.Errorf("failed to decode response from plugin %s: %w", p.cfg.Name, err)
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("plugin %s returned error %d: %s", p.cfg.Name, resp.Error.Code, resp.Error.Message)
+	}
+	if resp.Result == nil {
+		return "", fmt.Errorf("plugin %s returned no result", p.cfg.Name)
+	}
+	if resp.Result.Error != "" {
+		return resp.Result.Output, fmt.Errorf("%s", resp.Result.Error)
+	}
+
+	return resp.Result.Output, nil
+}
+
+// callStdio spawns Command as a subprocess, writes the request to its
+// stdin, and reads the response from its stdout.
+func (p *Plugin) callStdio(ctx context.Context, payload []byte) ([]byte, error) {
+	parts := strings.Fields(p.cfg.Command)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("plugin %s has no command configured", p.cfg.Name)
+	}
+
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("plugin %s exited with error: %w\nstderr: %s", p.cfg.Name, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// callSocket dials Socket and exchanges a single request/response over
+// it, honoring ctx the same way callStdio gets cancellation for free
+// from exec.CommandContext: a deadline on ctx becomes a socket deadline,
+// and canceling ctx closes the connection to unblock the write/read
+// below instead of hanging past --shutdown-timeout or ignoring SIGINT.
+func (p *Plugin) callSocket(ctx context.Context, payload []byte) ([]byte, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", p.cfg.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to plugin %s at %s: %w", p.cfg.Name, p.cfg.Socket, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stopWatch:
+		}
+	}()
+
+	if _, err := conn.Write(append(payload, '\n')); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugin %s call canceled: %w", p.cfg.Name, ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to write to plugin %s: %w", p.cfg.Name, err)
+	}
+
+	raw, err := io.ReadAll(conn)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugin %s call canceled: %w", p.cfg.Name, ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to read from plugin %s: %w", p.cfg.Name, err)
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestCallStdio(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "plugin.sh")
+	content := "#!/bin/sh\ncat > /dev/null\necho '{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"output\":\"ok\"}}'\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("failed to write plugin script: %v", err)
+	}
+
+	p := New(Config{Name: "test", Command: "sh " + script})
+	output, err := p.Call(context.Background(), types.Task{Name: "t"})
+	if err != nil {
+		t.Fatalf("Call returned error: %v", err)
+	}
+	if output != "ok" {
+		t.Errorf("output = %q, want %q", output, "ok")
+	}
+}
+
+func TestCallStdioPluginError(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "plugin.sh")
+	content := "#!/bin/sh\ncat > /dev/null\necho '{\"jsonrpc\":\"2.0\",\"id\":1,\"error\":{\"code\":1,\"message\":\"boom\"}}'\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("failed to write plugin script: %v", err)
+	}
+
+	p := New(Config{Name: "test", Command: "sh " + script})
+	if _, err := p.Call(context.Background(), types.Task{Name: "t"}); err == nil {
+		t.Fatal("expected error from plugin error response, got nil")
+	}
+}
+
+// unixEchoServer listens on a fresh socket under t.TempDir(), accepts one
+// connection, and responds to it with respond. It returns the socket path.
+func unixEchoServer(t *testing.T, respond func(conn net.Conn)) string {
+	t.Helper()
+
+	socketPath := filepath.Join(t.TempDir(), "plugin.sock")
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen on unix socket: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		respond(conn)
+	}()
+
+	return socketPath
+}
+
+func TestCallSocket(t *testing.T) {
+	socketPath := unixEchoServer(t, func(conn net.Conn) {
+		bufio.NewReader(conn).ReadString('\n')
+		conn.Write([]byte("{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"output\":\"ok\"}}\n"))
+	})
+
+	p := New(Config{Name: "test", Socket: socketPath})
+	output, err := p.Call(context.Background(), types.Task{Name: "t"})
+	if err != nil {
+		t.Fatalf("Call returned error: %v", err)
+	}
+	if output != "ok" {
+		t.Errorf("output = %q, want %q", output, "ok")
+	}
+}
+
+func TestCallSocketCancellation(t *testing.T) {
+	// The server accepts but never replies, simulating a hung plugin.
+	blockUntilClosed := make(chan struct{})
+	socketPath := unixEchoServer(t, func(conn net.Conn) {
+		<-blockUntilClosed
+	})
+	defer close(blockUntilClosed)
+
+	p := New(Config{Name: "test", Socket: socketPath})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.Call(ctx, types.Task{Name: "t"})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected error from a plugin call whose context expired, got nil")
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Call took %v to return after ctx deadline, wanted it to unblock promptly", elapsed)
+	}
+}
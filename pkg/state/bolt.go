@@ -0,0 +1,100 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const runsBucket = "runs"
+
+// BoltBackend persists runs to a local BoltDB file. It's taskflow's
+// default state backend: no server to run, safe for a single writer,
+// good enough for a CLI tool's crash-recovery needs.
+type BoltBackend struct {
+	db *bbolt.DB
+}
+
+// NewBoltBackend opens (creating if necessary) a BoltDB file at path.
+func NewBoltBackend(path string) (*BoltBackend, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(runsBucket))
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize state file %s: %w", path, err)
+	}
+
+	return &BoltBackend{db: db}, nil
+}
+
+// Close closes the underlying BoltDB file.
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
+
+func runKey(workflowName, runID string) []byte {
+	return []byte(workflowName + "/" + runID)
+}
+
+// Save persists run, overwriting any previous checkpoint for the same
+// workflow name + run ID.
+func (b *BoltBackend) Save(run *Run) error {
+	run.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode run %s/%s: %w", run.WorkflowName, run.RunID, err)
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(runsBucket)).Put(runKey(run.WorkflowName, run.RunID), data)
+	})
+}
+
+// Load retrieves a previously saved run.
+func (b *BoltBackend) Load(workflowName, runID string) (*Run, error) {
+	var run Run
+
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(runsBucket)).Get(runKey(workflowName, runID))
+		if data == nil {
+			return fmt.Errorf("no run %q found for workflow %q", runID, workflowName)
+		}
+		return json.Unmarshal(data, &run)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// List returns every run persisted in the backend, across all workflows.
+func (b *BoltBackend) List() ([]*Run, error) {
+	var runs []*Run
+
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(runsBucket)).ForEach(func(_, v []byte) error {
+			var run Run
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			runs = append(runs, &run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,22 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// TaskID returns a stable hash of a task's definition. Engine.Run
+// compares it against a checkpointed result's TaskID on --resume so a
+// task whose YAML changed underneath a run is re-executed rather than
+// trusted from the old checkpoint.
+func TaskID(task types.Task) string {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return task.Name
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
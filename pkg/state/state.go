@@ -0,0 +1,48 @@
+// Package state defines the Backend interface Engine checkpoints through
+// and a JSON-friendly Run snapshot; BoltBackend in bolt.go is the only
+// implementation today.
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// Run is a single checkpointed workflow execution: every task result
+// recorded so far, keyed by task name, plus the task graph as it stood
+// at that point. Tasks is needed (not just Results) because discovery/
+// for_each/matrix tasks splice generated child tasks into the graph at
+// runtime; persisting that expanded shape lets a resumed run restore
+// those children - and their depends_on rewiring - instead of only the
+// statically-parsed YAML.
+type Run struct {
+	WorkflowName string                       `json:"workflow_name"`
+	RunID        string                       `json:"run_id"`
+	StartedAt    time.Time                    `json:"started_at"`
+	UpdatedAt    time.Time                    `json:"updated_at"`
+	Results      map[string]*types.TaskResult `json:"results"`
+	Tasks        []types.Task                 `json:"tasks,omitempty"`
+}
+
+// Backend persists and retrieves Runs. BoltBackend is the default;
+// --state-backend selects among registered backends so SQL/Redis
+// implementations can be added later without changing callers.
+type Backend interface {
+	Save(run *Run) error
+	Load(workflowName, runID string) (*Run, error)
+	List() ([]*Run, error)
+	Close() error
+}
+
+// Open opens the backend named by kind at path. "bolt" (the default) is
+// the only backend taskflow ships with today.
+func Open(kind, path string) (Backend, error) {
+	switch kind {
+	case "", "bolt":
+		return NewBoltBackend(path)
+	default:
+		return nil, fmt.Errorf("unknown state backend %q (only \"bolt\" is currently supported)", kind)
+	}
+}
@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 // Workflow represents a complete workflow definition
 type Workflow struct {
@@ -8,12 +12,22 @@ type Workflow struct {
 	Description string            `yaml:"description"`
 	Variables   map[string]string `yaml:"variables"`
 	Tasks       []Task            `yaml:"tasks"`
+	// Plugins maps a task type name to the out-of-process plugin that
+	// handles it: either a command spawned over stdio, e.g.
+	// `docker: ./plugins/taskflow-docker`, or a `unix://` address of a
+	// socket already being listened on, e.g.
+	// `docker: unix:///var/run/taskflow-docker.sock`.
+	Plugins map[string]string `yaml:"plugins,omitempty"`
+	// MaxParallel caps how many parallel tasks the engine's worker pool
+	// runs at once. Zero (the default) means runtime.NumCPU(); --max-procs
+	// on the CLI overrides this.
+	MaxParallel int `yaml:"max_parallel,omitempty"`
 }
 
 // Task represents a single task in the workflow
 type Task struct {
 	Name            string            `yaml:"name"`
-	Type            string            `yaml:"type"` // shell, http, file
+	Type            string            `yaml:"type"` // shell, http, file, discovery
 	Command         string            `yaml:"command,omitempty"`
 	Script          string            `yaml:"script,omitempty"`
 	URL             string            `yaml:"url,omitempty"`
@@ -31,6 +45,41 @@ type Task struct {
 	Timeout         time.Duration     `yaml:"timeout,omitempty"`
 	ContinueOnError bool              `yaml:"continue_on_error,omitempty"`
 	Parallel        bool              `yaml:"parallel,omitempty"`
+	Discovery       *DiscoveryConfig  `yaml:"discovery,omitempty"`
+	Vars            map[string]string `yaml:"vars,omitempty"`
+	Resources       ResourceConfig    `yaml:"resources,omitempty"`
+	// ForEach yields a list to expand this task over - either a bracketed
+	// list literal ("[prod, staging, dev]" or "[\"a\", \"b\"]") or an
+	// expression evaluating to a list, e.g. a dotted path onto a prior
+	// task's JSON output (tasks.discover.json.hosts). Each expansion gets
+	// an ${item} variable. Mutually exclusive with Matrix.
+	ForEach string `yaml:"for_each,omitempty"`
+	// Matrix expands this task into the Cartesian product of its named
+	// axes, e.g. {region: [us-east, eu-west], color: [blue, green]}
+	// produces 4 child tasks, each with ${matrix.region}/${matrix.color}.
+	Matrix map[string][]string `yaml:"matrix,omitempty"`
+}
+
+// ResourceConfig declares a task's resource footprint. CPU and MemoryMB
+// are informational only (the engine logs a warning when they are set and
+// does not enforce them as limits); ConcurrencyGroup is enforced: tasks sharing a non-empty
+// group serialize against each other even when marked Parallel.
+type ResourceConfig struct {
+	CPU              float64 `yaml:"cpu,omitempty"`
+	MemoryMB         int     `yaml:"memory_mb,omitempty"`
+	ConcurrencyGroup string  `yaml:"concurrency_group,omitempty"`
+}
+
+// DiscoveryConfig configures a "discovery" task: it queries a service
+// registry for live targets and expands Task into one copy per target,
+// with ${discovered.host}/${discovered.port} available to that copy.
+type DiscoveryConfig struct {
+	Provider   string `yaml:"provider"` // consul, dns, http
+	Service    string `yaml:"service,omitempty"`
+	ConsulAddr string `yaml:"consul_addr,omitempty"`
+	DNSName    string `yaml:"dns_name,omitempty"`
+	URL        string `yaml:"url,omitempty"`
+	Task       *Task  `yaml:"task"`
 }
 
 // RetryConfig defines retry behavior for a task
@@ -47,4 +96,63 @@ type TaskResult struct {
 	Output    string
 	StartTime time.Time
 	EndTime   time.Time
+	// RunID identifies the persisted run this result belongs to, and
+	// TaskID is a stable hash of the task definition that produced it
+	// (see pkg/state). RunID is empty unless state persistence is enabled;
+	// the engine sets TaskID on every task it executes or expands.
+	RunID  string `yaml:"-"`
+	TaskID string `yaml:"-"`
+}
+
+// resultJSON is TaskResult's on-the-wire shape. Error is an interface and
+// can't round-trip through encoding/json directly, so it's flattened to
+// a plain string.
+type resultJSON struct {
+	TaskName  string    `json:"task_name"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Output    string    `json:"output"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	RunID     string    `json:"run_id,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, used by pkg/state to persist
+// checkpointed results.
+func (r TaskResult) MarshalJSON() ([]byte, error) {
+	rj := resultJSON{
+		TaskName:  r.TaskName,
+		Success:   r.Success,
+		Output:    r.Output,
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+		RunID:     r.RunID,
+		TaskID:    r.TaskID,
+	}
+	if r.Error != nil {
+		rj.Error = r.Error.Error()
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, restoring Error as a plain
+// error value carrying the original message.
+func (r *TaskResult) UnmarshalJSON(data []byte) error {
+	var rj resultJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+
+	r.TaskName = rj.TaskName
+	r.Success = rj.Success
+	r.Output = rj.Output
+	r.StartTime = rj.StartTime
+	r.EndTime = rj.EndTime
+	r.RunID = rj.RunID
+	r.TaskID = rj.TaskID
+	if rj.Error != "" {
+		r.Error = errors.New(rj.Error)
+	}
+	return nil
 }
@@ -2,12 +2,21 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/BaseMax/go-taskflow/pkg/executor"
+	"github.com/BaseMax/go-taskflow/pkg/expr"
+	"github.com/BaseMax/go-taskflow/pkg/logger"
+	"github.com/BaseMax/go-taskflow/pkg/plugin"
+	"github.com/BaseMax/go-taskflow/pkg/state"
 	"github.com/BaseMax/go-taskflow/pkg/types"
 )
 
@@ -17,30 +26,144 @@ type Engine struct {
 	executor *executor.Executor
 	results  map[string]*types.TaskResult
 	mu       sync.Mutex
+	logger   *logger.Logger
+
+	subMu       sync.Mutex
+	subscribers []chan Event
+
+	state     state.Backend
+	runID     string
+	resume    bool
+	startedAt time.Time
+
+	maxParallel int
+
+	groupMu sync.Mutex
+	groups  map[string]*sync.Mutex
 }
 
 // NewEngine creates a new workflow engine
 func NewEngine(wf *types.Workflow) *Engine {
-	return &Engine{
+	e := &Engine{
 		workflow: wf,
 		executor: executor.NewExecutor(wf.Variables),
 		results:  make(map[string]*types.TaskResult),
+		logger:   logger.New(logger.LevelInfo, logger.FormatText, os.Stderr),
 	}
+	// Let ${tasks.<name>.<field>} templates in Command/URL/Body/Headers/
+	// FilePath/SourcePath/DestPath see the same task results conditions
+	// already can through exprContext/evaluateCondition.
+	e.executor.SetTaskResults(e.taskContexts)
+	return e
 }
 
-// Run executes the workflow
-func (e *Engine) Run(ctx context.Context) ([]*types.TaskResult, error) {
-	// Build dependency graph
-	taskMap := make(map[string]*types.Task)
-	for i := range e.workflow.Tasks {
-		taskMap[e.workflow.Tasks[i].Name] = &e.workflow.Tasks[i]
+// RegisterPlugin routes any task whose type matches name to the
+// out-of-process plugin p instead of a builtin handler.
+func (e *Engine) RegisterPlugin(name string, p *plugin.Plugin) {
+	e.executor.RegisterPlugin(name, p)
+}
+
+// SetLogger overrides the engine's logger, used by the CLI to apply
+// --log-format/--log-level.
+func (e *Engine) SetLogger(l *logger.Logger) {
+	e.logger = l
+}
+
+// SetMaxParallel overrides how many parallel tasks the worker pool runs
+// at once, used by the CLI's --max-procs flag. A value <= 0 leaves the
+// workflow's own `max_parallel` (falling back to runtime.NumCPU()) in
+// effect.
+func (e *Engine) SetMaxParallel(n int) {
+	e.maxParallel = n
+}
+
+// Results returns every task result recorded so far. Safe to call while
+// Run is still in progress on another goroutine, e.g. by a caller
+// enforcing its own shutdown timeout that wants whatever completed
+// before giving up on in-flight tasks.
+func (e *Engine) Results() []*types.TaskResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	results := make([]*types.TaskResult, 0, len(e.results))
+	for _, result := range e.results {
+		results = append(results, result)
+	}
+	return results
+}
+
+// workerLimit resolves the parallel worker pool size: an explicit
+// SetMaxParallel call wins, then the workflow's `max_parallel`, then
+// runtime.NumCPU().
+func (e *Engine) workerLimit() int {
+	if e.maxParallel > 0 {
+		return e.maxParallel
 	}
+	if e.workflow.MaxParallel > 0 {
+		return e.workflow.MaxParallel
+	}
+	return runtime.NumCPU()
+}
+
+// groupMutex returns the shared mutex for a concurrency_group name,
+// creating it on first use. Tasks in the same group serialize against
+// each other even when run through executeParallel.
+func (e *Engine) groupMutex(name string) *sync.Mutex {
+	e.groupMu.Lock()
+	defer e.groupMu.Unlock()
+
+	if e.groups == nil {
+		e.groups = make(map[string]*sync.Mutex)
+	}
+	m, ok := e.groups[name]
+	if !ok {
+		m = &sync.Mutex{}
+		e.groups[name] = m
+	}
+	return m
+}
+
+// EnableState turns on checkpointing: after every task finishes, the
+// engine's accumulated results are saved to backend under runID. If
+// resume is true, Run loads any existing checkpoint for runID first and
+// skips tasks that already succeeded there.
+func (e *Engine) EnableState(backend state.Backend, runID string, resume bool) {
+	e.state = backend
+	e.runID = runID
+	e.resume = resume
+}
+
+// Run executes the workflow
+func (e *Engine) Run(ctx context.Context) (results []*types.TaskResult, err error) {
+	defer func() {
+		e.emit(Event{Type: EventWorkflowFinished, Success: err == nil, Error: err})
+		e.closeSubscribers()
+	}()
 
 	// Execute tasks respecting dependencies
 	executed := make(map[string]bool)
 	var allResults []*types.TaskResult
 
+	e.startedAt = time.Now()
+	if e.state != nil && e.resume {
+		e.resumeFrom(executed, &allResults)
+	}
+
+	// Build dependency graph. This must run after resumeFrom: a
+	// previous run may have spliced discovery/for_each/matrix children
+	// into e.workflow.Tasks, and resumeFrom restores that expanded
+	// shape (from the checkpoint) before we index it here.
+	taskMap := make(map[string]*types.Task)
+	for i := range e.workflow.Tasks {
+		taskMap[e.workflow.Tasks[i].Name] = &e.workflow.Tasks[i]
+	}
+
 	for len(executed) < len(e.workflow.Tasks) {
+		if ctx.Err() != nil {
+			e.logger.Warn("shutdown in progress, not scheduling further tasks", logger.Fields{"error": ctx.Err().Error()})
+			return allResults, ctx.Err()
+		}
+
 		// Find tasks that can be executed (all dependencies met)
 		var readyTasks []types.Task
 		for _, task := range e.workflow.Tasks {
@@ -67,6 +190,50 @@ func (e *Engine) Run(ctx context.Context) ([]*types.TaskResult, error) {
 			break
 		}
 
+		// Expand discovery tasks into concrete child tasks before scheduling.
+		// Each child is spliced into e.workflow.Tasks so it flows through the
+		// normal dependency loop on the next iteration.
+		var remainingReady []types.Task
+		for _, task := range readyTasks {
+			if task.Type == "discovery" {
+				result := e.expandDiscoveryTask(ctx, task)
+				allResults = append(allResults, result)
+				executed[task.Name] = true
+				e.storeResult(result)
+
+				if !result.Success && !task.ContinueOnError {
+					return allResults, fmt.Errorf("discovery task %s failed: %v", task.Name, result.Error)
+				}
+				continue
+			}
+			remainingReady = append(remainingReady, task)
+		}
+		readyTasks = remainingReady
+
+		// Expand for_each/matrix tasks into one concrete child per
+		// item/combination, the same way discovery tasks expand above.
+		var afterLoopExpansion []types.Task
+		for _, task := range readyTasks {
+			if task.ForEach == "" && len(task.Matrix) == 0 {
+				afterLoopExpansion = append(afterLoopExpansion, task)
+				continue
+			}
+
+			result := e.expandLoopTask(task)
+			allResults = append(allResults, result)
+			executed[task.Name] = true
+			e.storeResult(result)
+
+			if !result.Success && !task.ContinueOnError {
+				return allResults, fmt.Errorf("loop task %s failed: %v", task.Name, result.Error)
+			}
+		}
+		readyTasks = afterLoopExpansion
+
+		if len(readyTasks) == 0 {
+			continue
+		}
+
 		// Group tasks for parallel execution
 		var parallelTasks, sequentialTasks []types.Task
 		for _, task := range readyTasks {
@@ -106,8 +273,44 @@ func (e *Engine) Run(ctx context.Context) ([]*types.TaskResult, error) {
 
 // executeTask executes a single task with retry logic
 func (e *Engine) executeTask(ctx context.Context, task types.Task) *types.TaskResult {
+	e.emit(Event{Type: EventTaskStarted, TaskName: task.Name})
+
+	if task.Resources.CPU > 0 || task.Resources.MemoryMB > 0 {
+		e.logger.Warn("resources.cpu/memory_mb are declarative only and not enforced; only concurrency_group is", logger.Fields{"task": task.Name})
+	}
+
+	result := e.doExecuteTask(ctx, task)
+	result.TaskID = state.TaskID(task)
+
+	e.emit(Event{
+		Type:       EventTaskFinished,
+		TaskName:   result.TaskName,
+		Success:    result.Success,
+		Error:      result.Error,
+		DurationMS: result.EndTime.Sub(result.StartTime).Milliseconds(),
+	})
+	e.logger.Info("task finished", logger.Fields{
+		"task":        result.TaskName,
+		"status":      taskStatus(result.Success),
+		"duration_ms": result.EndTime.Sub(result.StartTime).Milliseconds(),
+	})
+
+	return result
+}
+
+// taskStatus renders a TaskResult's success flag as the "status" field
+// value used in logs and events.
+func taskStatus(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failed"
+}
+
+// doExecuteTask runs a single task with retry logic.
+func (e *Engine) doExecuteTask(ctx context.Context, task types.Task) *types.TaskResult {
 	// Check condition
-	if task.Condition != "" && !e.evaluateCondition(task.Condition) {
+	if task.Condition != "" && !e.evaluateCondition(task.Condition, task.Vars) {
 		return &types.TaskResult{
 			TaskName:  task.Name,
 			Success:   true,
@@ -147,22 +350,40 @@ func (e *Engine) executeTask(ctx context.Context, task types.Task) *types.TaskRe
 		}
 
 		if attempt < maxAttempts-1 {
-			fmt.Printf("Task %s failed (attempt %d/%d), retrying...\n", task.Name, attempt+1, maxAttempts)
+			e.emit(Event{Type: EventTaskRetrying, TaskName: task.Name, Attempt: attempt + 1, Error: err})
+			e.logger.Warn("task failed, retrying", logger.Fields{
+				"task":     task.Name,
+				"attempt":  attempt + 1,
+				"attempts": maxAttempts,
+				"error":    err.Error(),
+			})
 		}
 	}
 
 	return result
 }
 
-// executeParallel executes multiple tasks in parallel
+// executeParallel executes multiple tasks in parallel, bounded by
+// workerLimit() and serialized within each task's concurrency_group.
 func (e *Engine) executeParallel(ctx context.Context, tasks []types.Task) []*types.TaskResult {
 	var wg sync.WaitGroup
 	results := make([]*types.TaskResult, len(tasks))
+	sem := make(chan struct{}, e.workerLimit())
 
 	for i, task := range tasks {
 		wg.Add(1)
 		go func(index int, t types.Task) {
 			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if t.Resources.ConcurrencyGroup != "" {
+				group := e.groupMutex(t.Resources.ConcurrencyGroup)
+				group.Lock()
+				defer group.Unlock()
+			}
+
 			results[index] = e.executeTask(ctx, t)
 		}(i, task)
 	}
@@ -188,55 +409,348 @@ func (e *Engine) dependenciesMet(task types.Task, executed map[string]bool) bool
 	return true
 }
 
-// storeResult stores a task result
-func (e *Engine) storeResult(result *types.TaskResult) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	e.results[result.TaskName] = result
+// expandDiscoveryTask queries the service registry configured on a
+// discovery task and splices one generated child task per discovered
+// target into the workflow. Each child is named "<parent>#<host>",
+// inherits the parent's dependencies, and gets a ${discovered.host} /
+// ${discovered.port} variable scope of its own. Any task that depended on
+// the parent is rewired to fan in from every expansion instead.
+func (e *Engine) expandDiscoveryTask(ctx context.Context, task types.Task) *types.TaskResult {
+	start := time.Now()
+
+	if task.Discovery == nil || task.Discovery.Task == nil {
+		err := fmt.Errorf("task %s has no discovery config or child task template", task.Name)
+		return &types.TaskResult{TaskName: task.Name, Success: false, Error: err, StartTime: start, EndTime: time.Now()}
+	}
+
+	targets, err := e.executor.Discover(ctx, *task.Discovery)
+	if err != nil {
+		return &types.TaskResult{TaskName: task.Name, Success: false, Error: err, StartTime: start, EndTime: time.Now()}
+	}
+
+	childNames := make([]string, 0, len(targets))
+	for _, target := range targets {
+		child := *task.Discovery.Task
+		child.Name = fmt.Sprintf("%s#%s", task.Name, target.Host)
+		child.DependsOn = append([]string{}, task.DependsOn...)
+		child.Vars = mergeVars(child.Vars, map[string]string{
+			"discovered.host": target.Host,
+			"discovered.port": strconv.Itoa(target.Port),
+		})
+		e.workflow.Tasks = append(e.workflow.Tasks, child)
+		childNames = append(childNames, child.Name)
+	}
+
+	for i := range e.workflow.Tasks {
+		e.workflow.Tasks[i].DependsOn = fanOutDependency(e.workflow.Tasks[i].DependsOn, task.Name, childNames)
+	}
+
+	return &types.TaskResult{
+		TaskName:  task.Name,
+		Success:   true,
+		Output:    fmt.Sprintf("discovered %d target(s)", len(targets)),
+		StartTime: start,
+		EndTime:   time.Now(),
+		TaskID:    state.TaskID(task),
+	}
+}
+
+// fanOutDependency replaces any occurrence of oldDep in dependsOn with
+// newDeps, used to rewire tasks that depended on a discovery task so they
+// instead depend on every target it expanded into.
+func fanOutDependency(dependsOn []string, oldDep string, newDeps []string) []string {
+	var result []string
+	for _, dep := range dependsOn {
+		if dep == oldDep {
+			result = append(result, newDeps...)
+			continue
+		}
+		result = append(result, dep)
+	}
+	return result
+}
+
+// mergeVars returns a new map containing base with overrides applied on top.
+func mergeVars(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// expandLoopTask expands a task with for_each or matrix set into one
+// concrete child task per item/combination, spliced into e.workflow.Tasks
+// so each flows through the normal dependency loop on the next iteration.
+// Any task that depended on the parent is rewired to fan in from every
+// expansion instead, mirroring expandDiscoveryTask.
+func (e *Engine) expandLoopTask(task types.Task) *types.TaskResult {
+	start := time.Now()
+
+	var instanceVars []map[string]string
+	var labels []string
+
+	switch {
+	case task.ForEach != "":
+		items, err := e.evalForEach(task)
+		if err != nil {
+			return &types.TaskResult{TaskName: task.Name, Success: false, Error: err, StartTime: start, EndTime: time.Now()}
+		}
+		for _, item := range items {
+			instanceVars = append(instanceVars, map[string]string{"item": item})
+			labels = append(labels, fmt.Sprintf("item=%s", item))
+		}
+
+	case len(task.Matrix) > 0:
+		axes := make([]string, 0, len(task.Matrix))
+		for axis := range task.Matrix {
+			axes = append(axes, axis)
+		}
+		sort.Strings(axes)
+
+		for _, combo := range cartesianProduct(task.Matrix, axes) {
+			vars := make(map[string]string, len(combo))
+			parts := make([]string, len(axes))
+			for i, axis := range axes {
+				vars["matrix."+axis] = combo[axis]
+				parts[i] = fmt.Sprintf("%s=%s", axis, combo[axis])
+			}
+			instanceVars = append(instanceVars, vars)
+			labels = append(labels, strings.Join(parts, ","))
+		}
+
+	default:
+		err := fmt.Errorf("task %s has neither for_each nor matrix set", task.Name)
+		return &types.TaskResult{TaskName: task.Name, Success: false, Error: err, StartTime: start, EndTime: time.Now()}
+	}
+
+	childNames := make([]string, 0, len(instanceVars))
+	for i, vars := range instanceVars {
+		child := task
+		child.ForEach = ""
+		child.Matrix = nil
+		child.Name = fmt.Sprintf("%s[%s]", task.Name, labels[i])
+		child.DependsOn = append([]string{}, task.DependsOn...)
+		child.Vars = mergeVars(child.Vars, vars)
+		e.workflow.Tasks = append(e.workflow.Tasks, child)
+		childNames = append(childNames, child.Name)
+	}
+
+	for i := range e.workflow.Tasks {
+		e.workflow.Tasks[i].DependsOn = fanOutDependency(e.workflow.Tasks[i].DependsOn, task.Name, childNames)
+	}
+
+	return &types.TaskResult{
+		TaskName:  task.Name,
+		Success:   true,
+		Output:    fmt.Sprintf("expanded into %d task(s)", len(childNames)),
+		StartTime: start,
+		EndTime:   time.Now(),
+		TaskID:    state.TaskID(task),
+	}
+}
+
+// evalForEach resolves a for_each expression into its list of items. It
+// first tries parsing the string directly as a bracketed list literal,
+// e.g. [prod, staging, dev] or ["a", "b", "c"]; if that fails it falls
+// back to evaluating it as an expression, e.g. tasks.discover.json.hosts
+// resolving to a JSON array produced by a prior task.
+func (e *Engine) evalForEach(task types.Task) ([]string, error) {
+	if items, ok := parseFlowList(task.ForEach); ok {
+		return items, nil
+	}
+
+	result, err := expr.Eval(task.ForEach, e.exprContext(task.Vars))
+	if err != nil {
+		return nil, fmt.Errorf("for_each %q is neither a list literal nor a valid expression: %w", task.ForEach, err)
+	}
+	list, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("for_each expression %q did not evaluate to a list (got %T)", task.ForEach, result)
+	}
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = fmt.Sprintf("%v", item)
+	}
+	return items, nil
 }
 
-// evaluateCondition evaluates a simple condition
-// Supports basic expressions like: ${VAR} == "value", ${VAR} != "value"
-func (e *Engine) evaluateCondition(condition string) bool {
-	// Replace variables
-	condition = e.replaceVariables(condition)
+// parseFlowList parses a bracketed, comma-separated list literal such as
+// `[prod, staging, dev]` or `["prod", "staging"]` into its items,
+// trimming whitespace and any surrounding quotes from each one. The
+// second return value is false if s isn't a list literal at all.
+func parseFlowList(s string) ([]string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, false
+	}
+
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return []string{}, true
+	}
+
+	parts := strings.Split(inner, ",")
+	items := make([]string, len(parts))
+	for i, part := range parts {
+		items[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return items, true
+}
 
-	// Simple evaluation - check for common patterns
-	if strings.Contains(condition, "==") {
-		parts := strings.Split(condition, "==")
-		if len(parts) == 2 {
-			left := strings.TrimSpace(parts[0])
-			right := strings.TrimSpace(strings.Trim(parts[1], "\"'"))
-			return left == right
+// cartesianProduct returns every combination of axis->value across
+// matrix, as maps keyed by axis name. axes fixes the iteration order so
+// repeated calls are deterministic.
+func cartesianProduct(matrix map[string][]string, axes []string) []map[string]string {
+	combos := []map[string]string{{}}
+	for _, axis := range axes {
+		var next []map[string]string
+		for _, combo := range combos {
+			for _, value := range matrix[axis] {
+				c := make(map[string]string, len(combo)+1)
+				for k, v := range combo {
+					c[k] = v
+				}
+				c[axis] = value
+				next = append(next, c)
+			}
 		}
+		combos = next
 	}
+	return combos
+}
 
-	if strings.Contains(condition, "!=") {
-		parts := strings.Split(condition, "!=")
-		if len(parts) == 2 {
-			left := strings.TrimSpace(parts[0])
-			right := strings.TrimSpace(strings.Trim(parts[1], "\"'"))
-			return left != right
+// resumeFrom loads a previous checkpoint for e.runID and marks every task
+// that already succeeded there as executed, so Run only re-runs
+// failed/pending tasks. A task whose definition changed since the
+// checkpoint (TaskID mismatch) is re-run from scratch.
+func (e *Engine) resumeFrom(executed map[string]bool, allResults *[]*types.TaskResult) {
+	run, err := e.state.Load(e.workflow.Name, e.runID)
+	if err != nil {
+		e.logger.Info("no existing checkpoint found, starting fresh", logger.Fields{"run_id": e.runID})
+		return
+	}
+
+	e.startedAt = run.StartedAt
+
+	// Restore the task graph as it stood at the last checkpoint,
+	// including any discovery/for_each/matrix children spliced in by
+	// expandDiscoveryTask/expandLoopTask and the depends_on rewiring
+	// fanOutDependency applied for them. Without this, a checkpointed
+	// parent task would be marked executed straight from taskMap below
+	// while its children - and the dependents rewired onto them - are
+	// silently dropped.
+	if len(run.Tasks) > 0 {
+		e.workflow.Tasks = append([]types.Task{}, run.Tasks...)
+	}
+
+	taskMap := make(map[string]*types.Task, len(e.workflow.Tasks))
+	for i := range e.workflow.Tasks {
+		taskMap[e.workflow.Tasks[i].Name] = &e.workflow.Tasks[i]
+	}
+
+	for name, result := range run.Results {
+		task, ok := taskMap[name]
+		if !ok || !result.Success || result.TaskID != state.TaskID(*task) {
+			continue
 		}
+
+		executed[name] = true
+		e.results[name] = result
+		*allResults = append(*allResults, result)
+		e.logger.Info("resumed task from checkpoint", logger.Fields{"task": name, "run_id": e.runID})
+	}
+}
+
+// checkpoint persists the engine's accumulated results so a future
+// --resume can pick up from here.
+func (e *Engine) checkpoint() {
+	if e.state == nil {
+		return
+	}
+
+	e.mu.Lock()
+	snapshot := make(map[string]*types.TaskResult, len(e.results))
+	for name, result := range e.results {
+		snapshot[name] = result
 	}
+	e.mu.Unlock()
 
-	// If condition is just a variable, check if it's truthy
-	return condition != "" && condition != "false" && condition != "0"
+	run := &state.Run{
+		WorkflowName: e.workflow.Name,
+		RunID:        e.runID,
+		StartedAt:    e.startedAt,
+		Results:      snapshot,
+		Tasks:        append([]types.Task{}, e.workflow.Tasks...),
+	}
+	if err := e.state.Save(run); err != nil {
+		e.logger.Warn("failed to checkpoint run state", logger.Fields{"run_id": e.runID, "error": err.Error()})
+	}
 }
 
-// replaceVariables replaces ${VAR} patterns with actual variable values
-func (e *Engine) replaceVariables(input string) string {
-	result := input
-	for key, value := range e.workflow.Variables {
-		result = strings.ReplaceAll(result, "${"+key+"}", value)
+// storeResult stores a task result and, if state persistence is
+// enabled, checkpoints the run.
+func (e *Engine) storeResult(result *types.TaskResult) {
+	e.mu.Lock()
+	result.RunID = e.runID
+	e.results[result.TaskName] = result
+	e.mu.Unlock()
+
+	e.checkpoint()
+}
+
+// evaluateCondition evaluates a task's condition as a full expression,
+// e.g. `tasks.build.success && tasks.api.json.status == "ok"`. vars is
+// the task's own per-instance scope (e.g. ${item}/${discovered.host}/
+// ${matrix.region} on an expanded discovery/for_each/matrix child),
+// merged on top of the workflow's variables so the condition can
+// reference it. A condition that fails to evaluate is treated as false
+// rather than aborting the task.
+func (e *Engine) evaluateCondition(condition string, vars map[string]string) bool {
+	val, err := expr.Eval(condition, e.exprContext(vars))
+	if err != nil {
+		e.logger.Warn("condition failed to evaluate, treating as false", logger.Fields{
+			"condition": condition,
+			"error":     err.Error(),
+		})
+		return false
 	}
-	// Also check task results
+	return expr.Truthy(val)
+}
+
+// exprContext builds the expr.Context exposing workflow variables (with
+// overrides, typically a task's own Vars, taking precedence), the
+// process environment, and every task result recorded so far to
+// conditions and templates.
+func (e *Engine) exprContext(overrides map[string]string) expr.Context {
+	return expr.Context{Tasks: e.taskContexts(), Env: expr.EnvironMap(), Vars: mergeVars(e.workflow.Variables, overrides)}
+}
+
+// taskContexts snapshots every task result recorded so far as
+// expr.TaskContext values (including Output's JSON-parsed form, when
+// Output happens to be valid JSON), keyed by task name. Shared by
+// exprContext and, via Executor.SetTaskResults, by ${...} templating in
+// Command/URL/Body/Headers/FilePath/SourcePath/DestPath so both code
+// paths see the same tasks.<name>.<field> values.
+func (e *Engine) taskContexts() map[string]expr.TaskContext {
 	e.mu.Lock()
-	for taskName, taskResult := range e.results {
-		if taskResult.Success {
-			result = strings.ReplaceAll(result, "${"+taskName+".output}", taskResult.Output)
+	defer e.mu.Unlock()
+
+	tasks := make(map[string]expr.TaskContext, len(e.results))
+	for name, result := range e.results {
+		tc := expr.TaskContext{
+			Output:     result.Output,
+			Success:    result.Success,
+			DurationMS: result.EndTime.Sub(result.StartTime).Milliseconds(),
+		}
+		var parsed interface{}
+		if json.Unmarshal([]byte(result.Output), &parsed) == nil {
+			tc.JSON = parsed
 		}
+		tasks[name] = tc
 	}
-	e.mu.Unlock()
-	return result
+	return tasks
 }
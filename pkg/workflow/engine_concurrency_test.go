@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func sleepTask(name, group string, deps []string) types.Task {
+	return types.Task{
+		Name:      name,
+		Type:      "shell",
+		Command:   "sleep 0.2",
+		Parallel:  true,
+		DependsOn: deps,
+		Resources: types.ResourceConfig{ConcurrencyGroup: group},
+	}
+}
+
+// overlaps reports whether two [start, end) intervals share any instant.
+func overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+func TestExecuteParallelSerializesConcurrencyGroup(t *testing.T) {
+	wf := &types.Workflow{
+		Name:        "concurrency-group-test",
+		MaxParallel: 2,
+		Tasks: []types.Task{
+			sleepTask("a", "db", nil),
+			sleepTask("b", "db", nil),
+		},
+	}
+	e := NewEngine(wf)
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	byName := map[string]*types.TaskResult{}
+	for _, r := range results {
+		byName[r.TaskName] = r
+	}
+
+	a, b := byName["a"], byName["b"]
+	if overlaps(a.StartTime, a.EndTime, b.StartTime, b.EndTime) {
+		t.Errorf("tasks sharing a concurrency_group ran concurrently: a=[%v,%v] b=[%v,%v]", a.StartTime, a.EndTime, b.StartTime, b.EndTime)
+	}
+}
+
+func TestExecuteParallelBoundsConcurrencyToWorkerLimit(t *testing.T) {
+	const limit = 2
+	const taskCount = 6
+
+	wf := &types.Workflow{Name: "worker-pool-test", MaxParallel: limit}
+	e := NewEngine(wf)
+
+	var tasks []types.Task
+	for i := 0; i < taskCount; i++ {
+		tasks = append(tasks, sleepTask(fmt.Sprintf("t%d", i), "", nil))
+	}
+
+	results := e.executeParallel(context.Background(), tasks)
+	if len(results) != taskCount {
+		t.Fatalf("expected %d results, got %d", taskCount, len(results))
+	}
+
+	// At every task's start instant, count how many of the taskCount
+	// intervals contain it; the worker pool's semaphore must keep that
+	// count from ever exceeding limit.
+	for _, probe := range results {
+		concurrent := 0
+		for _, r := range results {
+			if overlaps(r.StartTime, r.EndTime, probe.StartTime, probe.StartTime.Add(time.Nanosecond)) {
+				concurrent++
+			}
+		}
+		if concurrent > limit {
+			t.Errorf("observed %d tasks running concurrently at %v, want at most %d (workerLimit)", concurrent, probe.StartTime, limit)
+		}
+	}
+}
+
+func TestExecuteParallelRunsUngroupedTasksConcurrently(t *testing.T) {
+	wf := &types.Workflow{
+		Name:        "no-group-test",
+		MaxParallel: 2,
+		Tasks: []types.Task{
+			sleepTask("a", "", nil),
+			sleepTask("b", "", nil),
+		},
+	}
+	e := NewEngine(wf)
+
+	start := time.Now()
+	results, err := e.Run(context.Background())
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	// Each task sleeps 0.2s; if they ran concurrently the whole run takes
+	// well under the 0.4s serial execution would need.
+	if elapsed >= 350*time.Millisecond {
+		t.Errorf("tasks without a shared concurrency_group ran serially: took %v", elapsed)
+	}
+}
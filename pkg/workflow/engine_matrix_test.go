@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestCartesianProduct(t *testing.T) {
+	matrix := map[string][]string{
+		"region": {"us-east", "eu-west"},
+		"color":  {"blue", "green"},
+	}
+	axes := []string{"color", "region"}
+
+	combos := cartesianProduct(matrix, axes)
+	if len(combos) != 4 {
+		t.Fatalf("expected 4 combinations, got %d: %v", len(combos), combos)
+	}
+
+	seen := map[string]bool{}
+	for _, combo := range combos {
+		seen[combo["color"]+"/"+combo["region"]] = true
+	}
+	for _, want := range []string{"blue/us-east", "blue/eu-west", "green/us-east", "green/eu-west"} {
+		if !seen[want] {
+			t.Errorf("missing combination %q in %v", want, combos)
+		}
+	}
+}
+
+func TestMatrixTaskExpandsIntoChildPerCombination(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "matrix-test",
+		Tasks: []types.Task{
+			{
+				Name:    "deploy",
+				Type:    "shell",
+				Command: "echo ${matrix.region}/${matrix.color}",
+				Matrix: map[string][]string{
+					"region": {"us-east", "eu-west"},
+					"color":  {"blue", "green"},
+				},
+			},
+			{Name: "after", Type: "shell", Command: "true", DependsOn: []string{"deploy"}},
+		},
+	}
+	e := NewEngine(wf)
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	byName := map[string]*types.TaskResult{}
+	var names []string
+	for _, r := range results {
+		byName[r.TaskName] = r
+		names = append(names, r.TaskName)
+	}
+	sort.Strings(names)
+
+	wantChildren := []string{
+		"deploy[color=blue,region=us-east]",
+		"deploy[color=blue,region=eu-west]",
+		"deploy[color=green,region=us-east]",
+		"deploy[color=green,region=eu-west]",
+	}
+	for _, want := range wantChildren {
+		child, ok := byName[want]
+		if !ok {
+			t.Fatalf("expected child task %q in results, got %v", want, names)
+		}
+		if !child.Success {
+			t.Errorf("child task %q did not succeed: %+v", want, child)
+		}
+	}
+
+	// The parent task itself records an expansion summary, and "after"
+	// (which depended on the parent) must have been fanned in from every
+	// child rather than running unexpanded.
+	if _, ok := byName["deploy"]; !ok {
+		t.Errorf("expected parent task %q's own expansion result in results, got %v", "deploy", names)
+	}
+	if after, ok := byName["after"]; !ok || !after.Success {
+		t.Errorf("expected after task to run successfully after all matrix children, got %+v", after)
+	}
+}
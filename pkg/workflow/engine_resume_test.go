@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/BaseMax/go-taskflow/pkg/state"
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestResumeRestoresExpandedForEachChildren(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "state.db")
+	backend, err := state.NewBoltBackend(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open state backend: %v", err)
+	}
+	defer backend.Close()
+
+	wf := &types.Workflow{
+		Name: "resume-expansion-test",
+		Tasks: []types.Task{
+			{Name: "expand", Type: "shell", Command: "true", ForEach: "[a, b]"},
+			{Name: "after", Type: "shell", Command: "true", DependsOn: []string{"expand"}},
+		},
+	}
+
+	e1 := NewEngine(wf)
+	e1.EnableState(backend, "run-1", false)
+	if _, err := e1.Run(context.Background()); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+
+	// A fresh engine over the ORIGINAL, unexpanded workflow definition,
+	// resuming the checkpoint written above.
+	wf2 := &types.Workflow{
+		Name: "resume-expansion-test",
+		Tasks: []types.Task{
+			{Name: "expand", Type: "shell", Command: "true", ForEach: "[a, b]"},
+			{Name: "after", Type: "shell", Command: "true", DependsOn: []string{"expand"}},
+		},
+	}
+	e2 := NewEngine(wf2)
+	e2.EnableState(backend, "run-1", true)
+
+	results, err := e2.Run(context.Background())
+	if err != nil {
+		t.Fatalf("resumed run failed: %v", err)
+	}
+
+	byName := map[string]bool{}
+	for _, r := range results {
+		byName[r.TaskName] = true
+	}
+
+	for _, want := range []string{"expand", "expand[item=a]", "expand[item=b]", "after"} {
+		if !byName[want] {
+			t.Errorf("expected task %q in resumed results, results were %v", want, resultNames(results))
+		}
+	}
+}
+
+func resultNames(results []*types.TaskResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.TaskName
+	}
+	return names
+}
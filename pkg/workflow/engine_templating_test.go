@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestCommandTemplatingSeesPriorTaskOutput(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "templating-test",
+		Tasks: []types.Task{
+			{Name: "build", Type: "shell", Command: "echo built"},
+			{Name: "report", Type: "shell", Command: "echo got=${tasks.build.output}", DependsOn: []string{"build"}},
+		},
+	}
+	e := NewEngine(wf)
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	byName := map[string]*types.TaskResult{}
+	for _, r := range results {
+		byName[r.TaskName] = r
+	}
+
+	report := byName["report"]
+	if report == nil || !report.Success {
+		t.Fatalf("expected report task to succeed, got %+v", report)
+	}
+	if want := "got=built\n"; report.Output != want {
+		t.Errorf("report output = %q, want %q (tasks.build.output was not substituted)", report.Output, want)
+	}
+}
+
+func TestConditionSeesTaskInstanceVars(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "condition-vars-test",
+		Tasks: []types.Task{
+			{
+				Name:      "check",
+				Type:      "shell",
+				Command:   "echo ran",
+				ForEach:   `[us-east, eu-west]`,
+				Condition: `item == "us-east"`,
+			},
+		},
+	}
+	e := NewEngine(wf)
+
+	results, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	byName := map[string]*types.TaskResult{}
+	for _, r := range results {
+		byName[r.TaskName] = r
+	}
+
+	usEast := byName["check[item=us-east]"]
+	if usEast == nil {
+		t.Fatalf("expected check[item=us-east] in results, got %v", resultNames(results))
+	}
+	if usEast.Output == "Skipped due to condition" {
+		t.Errorf("check[item=us-east] was skipped; condition could not see its own ${item}")
+	}
+
+	euWest := byName["check[item=eu-west]"]
+	if euWest == nil {
+		t.Fatalf("expected check[item=eu-west] in results, got %v", resultNames(results))
+	}
+	if euWest.Output != "Skipped due to condition" {
+		t.Errorf("check[item=eu-west] should have been skipped by its condition, got output %q", euWest.Output)
+	}
+}
@@ -0,0 +1,63 @@
+package workflow
+
+import "time"
+
+// EventType identifies what happened in a workflow run.
+type EventType string
+
+// Event types emitted by Engine.Run.
+const (
+	EventTaskStarted      EventType = "task_started"
+	EventTaskRetrying     EventType = "task_retrying"
+	EventTaskFinished     EventType = "task_finished"
+	EventWorkflowFinished EventType = "workflow_finished"
+)
+
+// Event is a single point-in-time occurrence during a workflow run,
+// delivered to anything subscribed via Engine.Subscribe.
+type Event struct {
+	Type       EventType
+	TaskName   string
+	Attempt    int
+	Success    bool
+	Error      error
+	DurationMS int64
+	Time       time.Time
+}
+
+// Subscribe registers ch to receive Events as the workflow runs and
+// returns it back as a receive-only channel for convenience. The engine
+// closes ch once the run finishes, after the EventWorkflowFinished event
+// has been delivered. Sends are non-blocking: a subscriber that isn't
+// keeping up misses events rather than stalling execution.
+func (e *Engine) Subscribe(ch chan Event) <-chan Event {
+	e.subMu.Lock()
+	defer e.subMu.Unlock()
+	e.subscribers = append(e.subscribers, ch)
+	return ch
+}
+
+// emit delivers evt to every subscriber without blocking.
+func (e *Engine) emit(evt Event) {
+	evt.Time = time.Now()
+
+	e.subMu.Lock()
+	defer e.subMu.Unlock()
+	for _, ch := range e.subscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// closeSubscribers closes every subscribed channel, signaling consumers
+// that no further events will arrive.
+func (e *Engine) closeSubscribers() {
+	e.subMu.Lock()
+	defer e.subMu.Unlock()
+	for _, ch := range e.subscribers {
+		close(ch)
+	}
+	e.subscribers = nil
+}
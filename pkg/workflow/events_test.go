@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+func TestSubscribeReceivesTaskAndWorkflowEvents(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "events-test",
+		Tasks: []types.Task{
+			{Name: "a", Type: "shell", Command: "echo hi"},
+		},
+	}
+	e := NewEngine(wf)
+	events := e.Subscribe(make(chan Event, 16))
+
+	if _, err := e.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var seen []EventType
+	for evt := range events {
+		seen = append(seen, evt.Type)
+	}
+
+	want := []EventType{EventTaskStarted, EventTaskFinished, EventWorkflowFinished}
+	if len(seen) != len(want) {
+		t.Fatalf("got %v events, want %v", seen, want)
+	}
+	for i, w := range want {
+		if seen[i] != w {
+			t.Errorf("event[%d] = %v, want %v", i, seen[i], w)
+		}
+	}
+}
+
+func TestSubscribeChannelClosedAfterRun(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "events-close-test",
+		Tasks: []types.Task{
+			{Name: "a", Type: "shell", Command: "true"},
+		},
+	}
+	e := NewEngine(wf)
+	events := e.Subscribe(make(chan Event, 16))
+
+	if _, err := e.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for range events {
+	}
+
+	if _, ok := <-events; ok {
+		t.Fatal("expected events channel to be closed after Run completes")
+	}
+}
+
+func TestEmitDoesNotBlockOnSlowSubscriber(t *testing.T) {
+	wf := &types.Workflow{
+		Name: "events-slow-subscriber-test",
+		Tasks: []types.Task{
+			{Name: "a", Type: "shell", Command: "true"},
+			{Name: "b", Type: "shell", Command: "true", DependsOn: []string{"a"}},
+		},
+	}
+	e := NewEngine(wf)
+	// An unbuffered, never-drained channel: emit must not block on it.
+	e.Subscribe(make(chan Event))
+
+	if _, err := e.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+}
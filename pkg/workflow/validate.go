@@ -0,0 +1,49 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/BaseMax/go-taskflow/pkg/expr"
+	"github.com/BaseMax/go-taskflow/pkg/types"
+)
+
+// Validate statically checks a workflow's task graph and expressions
+// without executing anything: every depends_on and condition must
+// reference a task that's actually declared in the workflow.
+func Validate(wf *types.Workflow) []error {
+	var problems []error
+
+	names := make(map[string]bool, len(wf.Tasks))
+	for _, task := range wf.Tasks {
+		if names[task.Name] {
+			problems = append(problems, fmt.Errorf("duplicate task name %q", task.Name))
+		}
+		names[task.Name] = true
+	}
+
+	for _, task := range wf.Tasks {
+		for _, dep := range task.DependsOn {
+			if !names[dep] {
+				problems = append(problems, fmt.Errorf("task %q depends on unknown task %q", task.Name, dep))
+			}
+		}
+
+		if task.Condition == "" {
+			continue
+		}
+
+		node, err := expr.Parse(task.Condition)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("task %q has an invalid condition: %w", task.Name, err))
+			continue
+		}
+
+		for _, ref := range expr.CollectTaskRefs(node) {
+			if !names[ref] {
+				problems = append(problems, fmt.Errorf("task %q condition references unknown task %q", task.Name, ref))
+			}
+		}
+	}
+
+	return problems
+}